@@ -0,0 +1,157 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/xlab/c-for-go/parser"
+	"github.com/xlab/c-for-go/translator"
+)
+
+// runCache remembers the inputs and outputs of a generation run, so the next run
+// can be skipped if neither the config, nor the headers, nor the output have changed.
+type runCache struct {
+	path string
+	key  string
+
+	Key     string            `json:"key"`
+	Inputs  map[string]string `json:"inputs"`
+	Outputs map[string]string `json:"outputs"`
+	// Diagnostics are replayed when the run is skipped.
+	Diagnostics []translator.Diagnostic `json:"diagnostics,omitempty"`
+}
+
+// openRunCache loads the cache entry of the config and output path pair. The key is a hash
+// of the generator binary, the config, the options and the predefined macros.
+func openRunCache(configPath, outputPath string) (*runCache, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return nil, err
+	}
+	absConfig, err := filepath.Abs(configPath)
+	if err != nil {
+		return nil, err
+	}
+	absOutput, err := filepath.Abs(outputPath)
+	if err != nil {
+		return nil, err
+	}
+	cfg, cfgData, err := loadProcessConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	predefined, err := parser.Predefined(cfg.Parser)
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha256.New()
+	if exe, err := os.Executable(); err != nil {
+		return nil, err
+	} else if err := hashFileTo(h, exe); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(h, "%s\x00%s\x00", absConfig, absOutput)
+	fmt.Fprintf(h, "nocgo=%v ccdefs=%v ccincl=%v maxmem=%s nostamp=%v verify=%v verifystub=%v\x00",
+		*noCGO, *ccDefs, *ccIncl, *maxMem, *nostamp, *verify, *verifyStub)
+	h.Write(cfgData)
+	io.WriteString(h, predefined)
+
+	pathSum := sha256.Sum256([]byte(absConfig + "\x00" + absOutput))
+	c := &runCache{
+		path: filepath.Join(cacheDir, "c-for-go", hex.EncodeToString(pathSum[:8])+".json"),
+		key:  hex.EncodeToString(h.Sum(nil)),
+	}
+	if data, err := ioutil.ReadFile(c.path); err == nil {
+		// a broken entry is as good as a missing one
+		json.Unmarshal(data, c)
+	}
+	return c, nil
+}
+
+// UpToDate reports whether the previous run has been done with the same key
+// and none of its inputs and outputs have changed since.
+func (c *runCache) UpToDate() bool {
+	if c.Key != c.key || len(c.Outputs) == 0 {
+		return false
+	}
+	for _, files := range []map[string]string{c.Inputs, c.Outputs} {
+		for path, sum := range files {
+			if fileSum(path) != sum {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// RemoveStale removes the outputs of the previous run that are not among the outputs
+// of the current one, the files changed since the previous run are left in place.
+func (c *runCache) RemoveStale(outputs []string) error {
+	current := make(map[string]bool, len(outputs))
+	for _, path := range outputs {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		current[path] = true
+	}
+	for path, sum := range c.Outputs {
+		if current[path] || fileSum(path) != sum {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Save records the files of a successful run.
+func (c *runCache) Save(inputs, outputs []string) error {
+	c.Key = c.key
+	c.Inputs = make(map[string]string, len(inputs))
+	for _, path := range inputs {
+		c.Inputs[path] = fileSum(path)
+	}
+	c.Outputs = make(map[string]string, len(outputs))
+	for _, path := range outputs {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		c.Outputs[path] = fileSum(path)
+	}
+	data, err := json.MarshalIndent(c, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path, data, 0644)
+}
+
+// fileSum returns the hex-encoded hash of the file contents,
+// or an empty string if the file cannot be read.
+func fileSum(path string) string {
+	h := sha256.New()
+	if err := hashFileTo(h, path); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func hashFileTo(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
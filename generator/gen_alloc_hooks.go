@@ -0,0 +1,150 @@
+package generator
+
+import "fmt"
+
+// helperFor returns the variant of the helper chosen by the generator options.
+func (gen *Generator) helperFor(h *Helper) *Helper {
+	if !gen.cfg.Options.AllocHooks {
+		return h
+	}
+	switch h {
+	case cgoAllocMap:
+		return tracedCgoAllocMap
+	case arenaHelper:
+		return tracedArenaHelper
+	}
+	return h
+}
+
+// cgoAllocMapSource returns the source of cgoAllocMap, the traced one reports
+// the allocations and releases to the allocation hook.
+func cgoAllocMapSource(traced bool) string {
+	traceAdd, traceFree, freeVars := "", "", "ptr"
+	if traced {
+		traceAdd = "\ntraceAlloc(AllocEventAlloc, ptr, size)"
+		traceFree = "\ntraceAlloc(AllocEventFree, ptr, size)"
+		freeVars = "ptr, size"
+	}
+	return fmt.Sprintf(`type cgoAllocMap struct {
+		mux sync.RWMutex
+		m   map[unsafe.Pointer]uintptr
+	}
+
+	var cgoAllocsUnknown = new(cgoAllocMap)
+
+	func (a *cgoAllocMap) Add(ptr unsafe.Pointer, size uintptr) {
+		a.mux.Lock()
+		defer a.mux.Unlock()
+
+		if a.m == nil {
+			a.m = make(map[unsafe.Pointer]uintptr)
+		}
+		a.m[ptr] = size%s
+	}
+
+	func (a *cgoAllocMap) IsEmpty() bool {
+		a.mux.RLock()
+		defer a.mux.RUnlock()
+
+		isEmpty := len(a.m) == 0
+		return isEmpty
+	}
+
+	func (a *cgoAllocMap) Borrow(b *cgoAllocMap) {
+		if b == nil || b.IsEmpty() {
+			return
+		}
+
+		b.mux.Lock()
+		defer b.mux.Unlock()
+		a.mux.Lock()
+		defer a.mux.Unlock()
+
+		for ptr, size := range b.m {
+			if a.m == nil {
+				a.m = make(map[unsafe.Pointer]uintptr)
+			}
+			a.m[ptr] = size
+			delete(b.m, ptr)
+		}
+	}
+
+	func (a *cgoAllocMap) Free() {
+		a.mux.Lock()
+		defer a.mux.Unlock()
+
+		for %s := range a.m {
+			C.free(ptr)
+			delete(a.m, ptr)%s
+		}
+	}`, traceAdd, freeVars, traceFree)
+}
+
+var (
+	tracedCgoAllocMap = &Helper{
+		Name:        "cgoAllocMap",
+		Description: "cgoAllocMap stores pointers to C allocated memory and its sizes for future reference.",
+		Source:      cgoAllocMapSource(true),
+		Requires:    Helpers{allocHook},
+	}
+	allocHook = &Helper{
+		Name: "SetAllocHook",
+		Description: "SetAllocHook sets the function called on each allocation and release of C memory\n" +
+			"made by the package for the calls, nil removes the hook.",
+		Source: `func SetAllocHook(hook func(event AllocEvent, ptr unsafe.Pointer, size uintptr)) {
+			allocHookValue.Store(allocHookFunc(hook))
+		}
+
+		// AllocEvent is the kind of the event reported to the allocation hook.
+		type AllocEvent int
+
+		const (
+			AllocEventAlloc AllocEvent = iota
+			AllocEventFree
+		)
+
+		// AllocStats is a snapshot of the allocation counters.
+		type AllocStats struct {
+			Allocs    int64
+			Frees     int64
+			LiveBytes int64
+		}
+
+		// Live returns the number of allocations not released yet.
+		func (s AllocStats) Live() int64 {
+			return s.Allocs - s.Frees
+		}
+
+		// LiveAllocations returns the allocation counters of the package.
+		func LiveAllocations() AllocStats {
+			return AllocStats{
+				Allocs:    atomic.LoadInt64(&allocCount),
+				Frees:     atomic.LoadInt64(&freeCount),
+				LiveBytes: atomic.LoadInt64(&liveBytes),
+			}
+		}
+
+		type allocHookFunc func(event AllocEvent, ptr unsafe.Pointer, size uintptr)
+
+		var (
+			allocCount     int64
+			freeCount      int64
+			liveBytes      int64
+			allocHookValue atomic.Value
+		)
+
+		func traceAlloc(event AllocEvent, ptr unsafe.Pointer, size uintptr) {
+			switch event {
+			case AllocEventAlloc:
+				atomic.AddInt64(&allocCount, 1)
+				atomic.AddInt64(&liveBytes, int64(size))
+			case AllocEventFree:
+				atomic.AddInt64(&freeCount, 1)
+				atomic.AddInt64(&liveBytes, -int64(size))
+			}
+			if hook, _ := allocHookValue.Load().(allocHookFunc); hook != nil {
+				hook(event, ptr, size)
+			}
+		}`,
+	}
+)
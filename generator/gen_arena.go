@@ -0,0 +1,203 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tl "github.com/xlab/c-for-go/translator"
+)
+
+// isUnpackedArg reports whether the argument is converted by an unpack helper that allocates
+// the C memory for the call, those are the slices and arrays of non-plain values.
+func isUnpackedArg(memTip tl.Tip, goSpec tl.GoTypeSpec) bool {
+	if _, ok := fromGoHelperMap[goSpec]; ok || goSpec.IsGoString() {
+		return false
+	}
+	isPlain := memTip.HasCLayout() || goSpec.IsPlain() || goSpec.IsPlainKind()
+	switch {
+	case !isPlain && (goSpec.Slices > 0 || len(goSpec.OuterArr) > 0), // ex: []string
+		isPlain && goSpec.Slices > 0 && len(goSpec.OuterArr) > 0, // ex: [4][]byte
+		isPlain && goSpec.Slices > 1:                             // ex: [][]byte
+		return true
+	}
+	return false
+}
+
+// isArenaArg reports whether the argument can be allocated from an arena,
+// the memory that C keeps after the call is never allocated from it.
+func isArenaArg(memTip, ownTip tl.Tip, goSpec tl.GoTypeSpec) bool {
+	if ownTip == tl.TipTransfer || len(ownTip.FreeWith()) > 0 {
+		return false
+	}
+	return isUnpackedArg(memTip, goSpec)
+}
+
+// hasArenaArgs reports whether any argument of the function can be allocated from an arena,
+// only such functions get the variants of ArenaCalls.
+func (gen *Generator) hasArenaArgs(decl *tl.CDecl) bool {
+	spec := decl.Spec.(*tl.CFunctionSpec)
+	ptrTipRx, typeTipRx, memTipRx := gen.tr.TipRxsForSpec(tl.TipScopeFunction, decl.Name, decl.Spec)
+	for i := range spec.Params {
+		if ptrTipRx.TipAt(i) == tl.TipPtrInst {
+			// the instance methods have a receiver already
+			return false
+		}
+	}
+	for i, param := range spec.Params {
+		goSpec := gen.tr.TranslateSpec(param.Spec, ptrTipRx.TipAt(i), typeTipRx.TipAt(i))
+		argTip := memTipRx.TipAt(i)
+		if !argTip.IsValid() {
+			argTip = gen.MemTipOf(param)
+		}
+		if isArenaArg(argTip, ownTipOf(ptrTipRx, memTipRx, i), goSpec) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeArenaFallback writes the call of the plain function made by the variant without an arena,
+// the arguments are then allocated from the C heap and released after the call.
+func (gen *Generator) writeArenaFallback(wr io.Writer, decl *tl.CDecl, goName []byte) {
+	spec := decl.Spec.(*tl.CFunctionSpec)
+	const public = false
+	names := make([]string, 0, len(spec.Params))
+	for _, param := range spec.Params {
+		names = append(names, string(gen.tr.TransformName(tl.TargetType, param.Name, public)))
+	}
+	fmt.Fprintln(wr, "if calls.arena == nil {")
+	if spec.Return != nil {
+		fmt.Fprintf(wr, "return %s(%s)\n", goName, strings.Join(names, ", "))
+	} else {
+		fmt.Fprintf(wr, "%s(%s)\nreturn\n", goName, strings.Join(names, ", "))
+	}
+	fmt.Fprintln(wr, "}")
+}
+
+// arenaSource returns the source of Arena, the traced one reports its allocations
+// and releases of C memory to the allocation hook.
+func arenaSource(traced bool) string {
+	freeVars, traceSpill, traceFree, traceGrow, traceMalloc := "mem", "", "", "", ""
+	if traced {
+		freeVars = "mem, size"
+		traceSpill = "\ntraceAlloc(AllocEventAlloc, mem, total)"
+		traceFree = "\ntraceAlloc(AllocEventFree, mem, size)"
+		traceGrow = `
+		if a.buf != nil {
+			traceAlloc(AllocEventFree, a.buf, a.size)
+		}`
+		traceMalloc = "\ntraceAlloc(AllocEventAlloc, a.buf, size)"
+	}
+	return fmt.Sprintf(`type Arena struct {
+		buf      unsafe.Pointer
+		size     uintptr
+		off      uintptr
+		overflow map[unsafe.Pointer]uintptr
+		spilled  uintptr
+	}
+
+	// NewArena allocates an arena of size bytes, the arena grows on Reset
+	// if the calls made since the previous Reset needed more.
+	func NewArena(size int) *Arena {
+		a := new(Arena)
+		a.grow(uintptr(size))
+		return a
+	}
+
+	// Reset reclaims the memory allocated from the arena, the C memory of the arguments
+	// passed to the calls made since the previous Reset must not be used after that.
+	func (a *Arena) Reset() {
+		for %[1]s := range a.overflow {
+			C.free(mem)
+			delete(a.overflow, mem)%[3]s
+		}
+		if a.spilled > 0 {
+			a.grow(a.size + a.spilled)
+			a.spilled = 0
+		}
+		a.off = 0
+	}
+
+	// Free releases the memory of the arena.
+	func (a *Arena) Free() {
+		a.Reset()
+		a.grow(0)
+	}
+
+	const arenaAlign = 16
+
+	func (a *Arena) alloc(n int, size uintptr) unsafe.Pointer {
+		total := (uintptr(n)*size + arenaAlign - 1) &^ (arenaAlign - 1)
+		if total == 0 {
+			total = arenaAlign
+		}
+		if a.buf == nil || a.off+total > a.size {
+			// doesn't fit, the arena grows by the overflow on Reset
+			mem := C.calloc(1, C.size_t(total))%[2]s
+			if a.overflow == nil {
+				a.overflow = make(map[unsafe.Pointer]uintptr)
+			}
+			a.overflow[mem] = total
+			a.spilled += total
+			return mem
+		}
+		mem := unsafe.Pointer(uintptr(a.buf) + a.off)
+		a.off += total
+		b := (*[1 << 30]byte)(mem)[:total:total]
+		for i := range b {
+			b[i] = 0
+		}
+		return mem
+	}
+
+	func (a *Arena) cString(str string) *C.char {
+		mem := a.alloc(len(str)+1, 1)
+		b := (*[1 << 30]byte)(mem)[:len(str)+1 : len(str)+1]
+		copy(b, str)
+		return (*C.char)(mem)
+	}
+
+	func (a *Arena) grow(size uintptr) {%[4]s
+		C.free(a.buf)
+		a.buf = nil
+		if size > 0 {
+			a.buf = C.malloc(C.size_t(size))%[5]s
+		}
+		a.size = size
+	}`, freeVars, traceSpill, traceFree, traceGrow, traceMalloc)
+}
+
+var (
+	arenaHelper = &Helper{
+		Name:        "Arena",
+		Description: arenaDescription,
+		Source:      arenaSource(false),
+	}
+	tracedArenaHelper = &Helper{
+		Name:        "Arena",
+		Description: arenaDescription,
+		Source:      arenaSource(true),
+		Requires:    Helpers{allocHook},
+	}
+)
+
+const arenaDescription = "Arena is a C memory buffer reused by the calls made through With, the arguments converted\n" +
+	"for those calls are allocated from the arena instead of the C heap. The memory is reclaimed\n" +
+	"at once by Reset, an Arena must not be used by several goroutines at the same time."
+
+var arenaCallsHelper = &Helper{
+	Name: "ArenaCalls",
+	Description: "ArenaCalls has the variants of the functions that allocate the C memory of the arguments\n" +
+		"from an arena, the memory stays in use until the arena is reset.",
+	Source: `type ArenaCalls struct {
+		arena *Arena
+	}
+
+	// With returns the variants of the functions allocating from the arena,
+	// with a nil arena those allocate from the C heap as the plain functions do.
+	func With(arena *Arena) ArenaCalls {
+		return ArenaCalls{arena: arena}
+	}`,
+	Requires: Helpers{arenaHelper},
+}
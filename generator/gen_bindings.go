@@ -189,9 +189,13 @@ func (gen *Generator) unpackObj(buf io.Writer, goSpec tl.GoTypeSpec, cgoSpec tl.
 	return nil
 }
 
-func (gen *Generator) unpackObjEx(buf io.Writer, goSpec tl.GoTypeSpec, cgoSpec tl.CGoSpec, level uint8) *Helper {
+func (gen *Generator) unpackObjEx(buf io.Writer, goSpec tl.GoTypeSpec, cgoSpec tl.CGoSpec, level uint8, arena bool) *Helper {
 	uplevel := level - 1
 	indices := genIndices("i", level)
+	if arena && goSpec == tl.StringSpec {
+		fmt.Fprintf(buf, "v%d[i%d] = arena.cString(x%s)\n", uplevel, uplevel, indices)
+		return nil
+	}
 	if getHelper, ok := fromGoHelperMapEx[goSpec]; ok {
 		helper := getHelper(gen, cgoSpec)
 		fmt.Fprintf(buf, "var allocs%d *cgoAllocMap\n", uplevel)
@@ -232,7 +236,7 @@ func cgoSpecArg(cgoSpec tl.CGoSpec, level uint8, isArg bool) string {
 	return cgoSpec.AtLevel(level)
 }
 
-func (gen *Generator) unpackArray(buf1 io.Writer, buf2 *reverseBuffer, cgoSpec tl.CGoSpec, level uint8, isArg bool) {
+func (gen *Generator) unpackArray(buf1 io.Writer, buf2 *reverseBuffer, cgoSpec tl.CGoSpec, level uint8, isArg, arena bool) {
 	uplevel := level - 1
 	if level == 0 {
 		h := gen.getAllocMemoryHelper(cgoSpec)
@@ -240,12 +244,8 @@ func (gen *Generator) unpackArray(buf1 io.Writer, buf2 *reverseBuffer, cgoSpec t
 		gen.submitHelper(sizeOfPtr)
 		gen.submitHelper(cgoAllocMap)
 
-		fmt.Fprintf(buf1, `allocs = new(cgoAllocMap)
-		defer runtime.SetFinalizer(&unpacked, func(*%s) {
-			go allocs.Free()
-		})`, cgoSpec)
-		fmt.Fprintf(buf1, "\n\nmem0 := %s(1)\n", h.Name)
-		fmt.Fprintf(buf1, "allocs.Add(mem0)\n")
+		writeUnpackedAllocs(buf1, cgoSpec, arena)
+		gen.writeUnpackAlloc(buf1, h, cgoSpec, "mem0", "1", arena)
 		fmt.Fprintf(buf1, "v0 := (*%s)(mem0)\n", cgoSpec)
 		fmt.Fprintf(buf1, "for i0 := range x {\n")
 		buf2.Linef("return\n")
@@ -262,8 +262,7 @@ func (gen *Generator) unpackArray(buf1 io.Writer, buf2 *reverseBuffer, cgoSpec t
 	gen.submitHelper(h)
 	gen.submitHelper(sizeOfPtr)
 	gen.submitHelper(cgoAllocMap)
-	fmt.Fprintf(buf1, "mem%d := %s(1)\n", level, h.Name)
-	fmt.Fprintf(buf1, "allocs.Add(mem%d)\n", level)
+	gen.writeUnpackAlloc(buf1, h, cgoSpec.SpecAtLevel(level), fmt.Sprintf("mem%d", level), "1", arena)
 	fmt.Fprintf(buf1, "v%d := (*%s)(mem%d)\n", level, cgoSpec.AtLevel(level), level)
 	fmt.Fprintf(buf1, "for i%d := range x%s {\n", level, genIndices("i", level))
 	buf2.Linef("v%d[i%d] = *(*%s)(mem%d)\n",
@@ -271,13 +270,43 @@ func (gen *Generator) unpackArray(buf1 io.Writer, buf2 *reverseBuffer, cgoSpec t
 	buf2.Linef("}\n")
 }
 
+// writeUnpackedAllocs starts the allocation map of an unpack helper, the map is released
+// once the unpacked value is unreachable, unless the memory comes from an arena.
+func writeUnpackedAllocs(buf io.Writer, cgoSpec tl.CGoSpec, arena bool) {
+	if arena {
+		fmt.Fprint(buf, "allocs = new(cgoAllocMap)\n\n")
+		return
+	}
+	fmt.Fprintf(buf, `allocs = new(cgoAllocMap)
+		defer runtime.SetFinalizer(&unpacked, func(*%s) {
+			go allocs.Free()
+		})`, cgoSpec)
+	fmt.Fprint(buf, "\n\n")
+}
+
+// writeUnpackAlloc allocates the memory for n values of the C type, either from
+// the C heap counting it into the allocation map, or from the arena.
+func (gen *Generator) writeUnpackAlloc(buf io.Writer, h *Helper, cgoSpec tl.CGoSpec, mem, n string, arena bool) {
+	size := gen.sizeOfValueConst(cgoSpec)
+	if arena {
+		fmt.Fprintf(buf, "%s := arena.alloc(%s, %s)\n", mem, n, size)
+		return
+	}
+	fmt.Fprintf(buf, "%s := %s(%s)\n", mem, h.Name, n)
+	if n == "1" {
+		fmt.Fprintf(buf, "allocs.Add(%s, %s)\n", mem, size)
+		return
+	}
+	fmt.Fprintf(buf, "allocs.Add(%s, uintptr(%s)*%s)\n", mem, n, size)
+}
+
 func notNilBarrier(buf io.Writer, name string) {
 	fmt.Fprintf(buf, `if %s == nil {
 		return nil, nil
 	}`, name)
 }
 
-func (gen *Generator) unpackSlice(buf1 io.Writer, buf2 *reverseBuffer, cgoSpec tl.CGoSpec, level uint8, isArg bool) {
+func (gen *Generator) unpackSlice(buf1 io.Writer, buf2 *reverseBuffer, cgoSpec tl.CGoSpec, level uint8, isArg, arena bool) {
 	uplevel := level - 1
 
 	if level == 0 {
@@ -290,13 +319,9 @@ func (gen *Generator) unpackSlice(buf1 io.Writer, buf2 *reverseBuffer, cgoSpec t
 		gen.submitHelper(sizeOfPtr)
 		gen.submitHelper(cgoAllocMap)
 
-		fmt.Fprintf(buf1, `allocs = new(cgoAllocMap)
-		defer runtime.SetFinalizer(&unpacked, func(*%s) {
-			go allocs.Free()
-		})`, cgoSpec)
-		fmt.Fprintf(buf1, "\n\nlen0 := len(x)\n")
-		fmt.Fprintf(buf1, "mem0 := %s(len0)\n", h.Name)
-		fmt.Fprintf(buf1, "allocs.Add(mem0)\n")
+		writeUnpackedAllocs(buf1, cgoSpec, arena)
+		fmt.Fprintf(buf1, "len0 := len(x)\n")
+		gen.writeUnpackAlloc(buf1, h, levelSpec, "mem0", "len0", arena)
 		fmt.Fprintf(buf1, `h0 := &sliceHeader{
 			Data: mem0,
 			Cap: len0,
@@ -318,8 +343,7 @@ func (gen *Generator) unpackSlice(buf1 io.Writer, buf2 *reverseBuffer, cgoSpec t
 	gen.submitHelper(h)
 	gen.submitHelper(sizeOfPtr)
 	fmt.Fprintf(buf1, "len%d := len(x%s)\n", level, indices)
-	fmt.Fprintf(buf1, "mem%d := %s(len%d)\n", level, h.Name, level)
-	fmt.Fprintf(buf1, "allocs.Add(mem%d)\n", level)
+	gen.writeUnpackAlloc(buf1, h, levelSpec, fmt.Sprintf("mem%d", level), fmt.Sprintf("len%d", level), arena)
 	fmt.Fprintf(buf1, `h%d := &sliceHeader{
 			Data: mem%d,
 			Cap: len%d,
@@ -335,7 +359,7 @@ func (gen *Generator) unpackSlice(buf1 io.Writer, buf2 *reverseBuffer, cgoSpec t
 
 func (gen *Generator) getAllocMemoryHelper(cgoSpec tl.CGoSpec) *Helper {
 	name := "alloc" + gen.getTypedHelperName("memory", cgoSpec)
-	sizeofConst := "sizeOf" + gen.getTypedHelperName("value", cgoSpec)
+	sizeofConst := gen.sizeOfValueConst(cgoSpec)
 	helper := &Helper{
 		Name: name,
 		Description: fmt.Sprintf(`%s allocates memory for type %s in C.
@@ -358,6 +382,12 @@ The caller is responsible for freeing the this memory via C.free.`, name, cgoSpe
 	return helper
 }
 
+// sizeOfValueConst returns the name of the const with the size of a value of the C type,
+// it's declared by the alloc helper of the type.
+func (gen *Generator) sizeOfValueConst(cgoSpec tl.CGoSpec) string {
+	return "sizeOf" + gen.getTypedHelperName("value", cgoSpec)
+}
+
 func (gen *Generator) getUnpackStringHelper(cgoSpec tl.CGoSpec) *Helper {
 	cgoSpec = tl.CGoSpec{
 		Pointers: 1,
@@ -400,7 +430,7 @@ func (gen *Generator) getUnpackMemoryStringHelper(cgoSpec tl.CGoSpec) *Helper {
 			ptr0 := C.CString(str)
 			mem0 := unsafe.Pointer(ptr0)
 			allocs0 := new(cgoAllocMap)
-			allocs0.Add(mem0)
+			allocs0.Add(mem0, uintptr(len(str)+1))
 			return ptr0, allocs0
 		}`, name, cgoSpec),
 		Requires: []*Helper{stringHeader, cgoAllocMap},
@@ -422,25 +452,42 @@ func goSpecArg(goSpec tl.GoTypeSpec, isArg bool) string {
 }
 
 func (gen *Generator) getUnpackHelper(goSpec tl.GoTypeSpec, cgoSpec tl.CGoSpec, isArg bool) *Helper {
+	return gen.unpackHelper(goSpec, cgoSpec, isArg, false)
+}
+
+// getUnpackArenaHelper returns the unpack helper of an argument that allocates the C memory from an arena.
+func (gen *Generator) getUnpackArenaHelper(goSpec tl.GoTypeSpec, cgoSpec tl.CGoSpec) *Helper {
+	return gen.unpackHelper(goSpec, cgoSpec, true, true)
+}
+
+func (gen *Generator) unpackHelper(goSpec tl.GoTypeSpec, cgoSpec tl.CGoSpec, isArg, arena bool) *Helper {
 	name := "unpack"
 	if isArg {
 		name += "Arg" + getHelperName(goSpec)
 	} else {
 		name += getHelperName(goSpec)
 	}
+	var arenaParam string
+	if arena {
+		name += "InArena"
+		arenaParam = "arena *Arena, "
+	}
 	code := new(bytes.Buffer)
-	fmt.Fprintf(code, "func %s(x %s) (unpacked %s, allocs *cgoAllocMap) {\n",
-		name, goSpecArg(goSpec, isArg), cgoSpecArg(cgoSpec, 0, isArg))
+	fmt.Fprintf(code, "func %s(%sx %s) (unpacked %s, allocs *cgoAllocMap) {\n",
+		name, arenaParam, goSpecArg(goSpec, isArg), cgoSpecArg(cgoSpec, 0, isArg))
 	h := &Helper{
 		Name:        name,
 		Description: fmt.Sprintf("%s transforms a sliced Go data structure into plain C format.", name),
 	}
+	if arena {
+		h.Requires = append(h.Requires, arenaHelper)
+	}
 	var level uint8
 	buf1 := new(bytes.Buffer)
 	buf2 := new(reverseBuffer)
 
 	for range goSpec.OuterArr.Sizes() {
-		gen.unpackArray(buf1, buf2, cgoSpec, level, isArg)
+		gen.unpackArray(buf1, buf2, cgoSpec, level, isArg, arena)
 		level++
 	}
 	goSpec.OuterArr = ""
@@ -448,7 +495,7 @@ func (gen *Generator) getUnpackHelper(goSpec tl.GoTypeSpec, cgoSpec tl.CGoSpec,
 	for goSpec.Slices > 1 {
 		goSpec.Slices--
 		gen.submitHelper(sliceHeader)
-		gen.unpackSlice(buf1, buf2, cgoSpec, level, isArg)
+		gen.unpackSlice(buf1, buf2, cgoSpec, level, isArg, arena)
 		level++
 	}
 	isSlice := goSpec.Slices > 0
@@ -460,23 +507,23 @@ func (gen *Generator) getUnpackHelper(goSpec tl.GoTypeSpec, cgoSpec tl.CGoSpec,
 	// 	unpackPlainSlice(buf1, cgoSpec, level)
 	case isPlain && isSlice:
 		gen.submitHelper(sliceHeader)
-		gen.unpackSlice(buf1, buf2, cgoSpec, level, isArg)
+		gen.unpackSlice(buf1, buf2, cgoSpec, level, isArg, arena)
 		goSpec.Slices = 0
-		if helper := gen.unpackObjEx(buf1, goSpec, cgoSpec, level+1); helper != nil {
+		if helper := gen.unpackObjEx(buf1, goSpec, cgoSpec, level+1, arena); helper != nil {
 			h.Requires = append(h.Requires, helper)
 		}
 	case isPlain:
 		unpackPlain(buf1, goSpec, cgoSpec, level)
 	case isSlice && cgoSpec.Base == "C.char" && cgoSpec.Pointers == 2:
 		gen.submitHelper(sliceHeader)
-		gen.unpackSlice(buf1, buf2, cgoSpec, level, isArg)
+		gen.unpackSlice(buf1, buf2, cgoSpec, level, isArg, arena)
 		goSpec.Slices = 0
-		if helper := gen.unpackObjEx(buf1, goSpec, cgoSpec, level+1); helper != nil {
+		if helper := gen.unpackObjEx(buf1, goSpec, cgoSpec, level+1, arena); helper != nil {
 			h.Requires = append(h.Requires, helper)
 		}
 	case isSlice:
 		gen.submitHelper(sliceHeader)
-		gen.unpackSlice(buf1, buf2, cgoSpec, level, isArg)
+		gen.unpackSlice(buf1, buf2, cgoSpec, level, isArg, arena)
 		goSpec.Slices = 0
 		if helper := gen.unpackObj(buf1, goSpec, cgoSpec, level+1); helper != nil {
 			h.Requires = append(h.Requires, helper)
@@ -505,7 +552,7 @@ func (gen *Generator) proxyValueFromGo(memTip tl.Tip, name string,
 	}
 	gen.submitHelper(cgoAllocMap)
 
-	isPlain := (memTip == tl.TipMemRaw) || goSpec.IsPlain() || goSpec.IsPlainKind()
+	isPlain := memTip.HasCLayout() || goSpec.IsPlain() || goSpec.IsPlainKind()
 	switch {
 	case !isPlain && (goSpec.Slices > 0 || len(goSpec.OuterArr) > 0), // ex: []string
 		isPlain && goSpec.Slices > 0 && len(goSpec.OuterArr) > 0, // ex: [4][]byte
@@ -561,7 +608,7 @@ func (gen *Generator) proxyValueFromGoEx(memTip tl.Tip, name string,
 	}
 	gen.submitHelper(cgoAllocMap)
 
-	isPlain := (memTip == tl.TipMemRaw) || goSpec.IsPlain() || goSpec.IsPlainKind()
+	isPlain := memTip.HasCLayout() || goSpec.IsPlain() || goSpec.IsPlainKind()
 	switch {
 	case !isPlain && (goSpec.Slices > 0 || len(goSpec.OuterArr) > 0), // ex: []string
 		isPlain && goSpec.Slices > 0 && len(goSpec.OuterArr) > 0, // ex: [4][]byte
@@ -617,11 +664,9 @@ func (gen *Generator) proxyArgFromGo(memTip tl.Tip, name string,
 	}
 	gen.submitHelper(cgoAllocMap)
 
-	isPlain := (memTip == tl.TipMemRaw) || goSpec.IsPlain() || goSpec.IsPlainKind()
+	isPlain := memTip.HasCLayout() || goSpec.IsPlain() || goSpec.IsPlainKind()
 	switch {
-	case !isPlain && (goSpec.Slices > 0 || len(goSpec.OuterArr) > 0), // ex: []string
-		isPlain && goSpec.Slices > 0 && len(goSpec.OuterArr) > 0, // ex: [4][]byte
-		isPlain && goSpec.Slices > 1:                             // ex: [][]byte
+	case isUnpackedArg(memTip, goSpec):
 		helper := gen.getUnpackHelper(goSpec, cgoSpec, true)
 		gen.submitHelper(helper)
 		proxy = fmt.Sprintf("%s(%s)", helper.Name, name)
@@ -663,6 +708,11 @@ func (gen *Generator) proxyArgFromGo(memTip tl.Tip, name string,
 			ptr, ptr, cgoSpec.AtLevel(0), ref, name)
 		return
 	default: // ex: *SomeType
+		if goSpec.Kind == tl.FunctionKind && goSpec.Pointers == 0 {
+			// Go funcs are passed to C by the callback proxy
+			proxy = fmt.Sprintf("%s.passValue()", name)
+			return
+		}
 		if goSpec.Pointers == 0 {
 			// proxy = fmt.Sprintf("%s.passValue()", name)
 			proxy = fmt.Sprintf("*(*%s)(unsafe.Pointer(&%s)), cgoAllocsUnknown", cgoSpec, name)
@@ -819,7 +869,7 @@ func (gen *Generator) getPackHelper(memTip tl.Tip, goSpec tl.GoTypeSpec, cgoSpec
 		level++
 	}
 	isSlice := goSpec.Slices > 0
-	isPlain := (memTip == tl.TipMemRaw) || goSpec.IsPlain() || goSpec.IsPlainKind()
+	isPlain := memTip.HasCLayout() || goSpec.IsPlain() || goSpec.IsPlainKind()
 
 	switch {
 	case isPlain && isSlice:
@@ -867,7 +917,7 @@ func (gen *Generator) proxyArgToGo(memTip tl.Tip, varName, ptrName string,
 		return proxy, helper.Nillable
 	}
 
-	isPlain := (memTip == tl.TipMemRaw) || goSpec.IsPlain() || goSpec.IsPlainKind()
+	isPlain := memTip.HasCLayout() || goSpec.IsPlain() || goSpec.IsPlainKind()
 	switch {
 	case !isPlain && (goSpec.Slices > 0 || len(goSpec.OuterArr) > 0), // ex: []string
 		isPlain && goSpec.Slices > 0 && len(goSpec.OuterArr) > 0, // ex: [4][]byte
@@ -916,7 +966,7 @@ func (gen *Generator) proxyValueToGo(memTip tl.Tip, varName, ptrName string,
 	}
 	gen.submitHelper(cgoAllocMap)
 
-	isPlain := (memTip == tl.TipMemRaw) || goSpec.IsPlain() || goSpec.IsPlainKind()
+	isPlain := memTip.HasCLayout() || goSpec.IsPlain() || goSpec.IsPlainKind()
 	switch {
 	case !isPlain && (goSpec.Slices > 0 || len(goSpec.OuterArr) > 0), // ex: []string
 		isPlain && goSpec.Slices > 0 && len(goSpec.OuterArr) > 0, // ex: [4][]byte
@@ -1014,7 +1064,7 @@ func (gen *Generator) proxyRetToGo(wr io.Writer, decl *tl.CDecl, memTip tl.Tip,
 	}
 	gen.submitHelper(cgoAllocMap)
 
-	isPlain := (memTip == tl.TipMemRaw) || goSpec.IsPlain() || goSpec.IsPlainKind()
+	isPlain := memTip.HasCLayout() || goSpec.IsPlain() || goSpec.IsPlainKind()
 	switch {
 	case !isPlain && (goSpec.Slices > 0 || len(goSpec.OuterArr) > 0), // ex: []string
 		isPlain && goSpec.Slices > 0 && len(goSpec.OuterArr) > 0: // ex: [4][]byte
@@ -1135,7 +1185,9 @@ func (gen *Generator) proxyRetToGo(wr io.Writer, decl *tl.CDecl, memTip tl.Tip,
 	}
 }
 
-func (gen *Generator) createProxies(funcName string, funcSpec tl.CType) (from, to []proxyDecl) {
+// createProxies returns the conversions of the arguments before and after the call,
+// with arena the unpacked arguments are allocated from the arena of the ArenaCalls receiver.
+func (gen *Generator) createProxies(funcName string, funcSpec tl.CType, arena bool) (from, to []proxyDecl) {
 	spec := funcSpec.(*tl.CFunctionSpec)
 	from = make([]proxyDecl, len(spec.Params))
 	to = make([]proxyDecl, 0, len(spec.Params))
@@ -1177,14 +1229,57 @@ func (gen *Generator) createProxies(funcName string, funcSpec tl.CType) (from, t
 			gen.submitHelper(safeString)
 			fmt.Fprintf(fromBuf, "%s = safeString(%s)\n", refName, refName)
 		}
-		fromProxy, nillable := gen.proxyArgFromGo(argTip, refName, goSpec, cgoSpec)
+		fromRef := refName
+		if _, ok := gen.ownedTypeOf(goSpec); ok {
+			fromRef = refName + ".pointer()"
+		}
+		fromProxy, nillable := gen.proxyArgFromGo(argTip, fromRef, goSpec, cgoSpec)
+		allocs := "_"
+		ownTip := ownTipOf(ptrTipRx, memTipRx, i)
+		if ownTip.IsValid() && cgoSpec.Pointers == 0 {
+			gen.tr.Diagnostics().Add(tl.DiagDegraded, param.Pos, funcName,
+				fmt.Sprintf("the %s tip is not applicable to the %s parameter", ownTip, param.Name))
+			ownTip = tl.NoTip
+		}
+		if arena && isArenaArg(argTip, ownTip, goSpec) {
+			helper := gen.getUnpackArenaHelper(goSpec, cgoSpec)
+			gen.submitHelper(helper)
+			fromProxy = fmt.Sprintf("%s(calls.arena, %s)", helper.Name, refName)
+		}
+		if goSpec.IsGoString() && (ownTip == tl.TipTransfer || len(ownTip.FreeWith()) > 0) {
+			// C gets a copy of the string in C memory, since it outlives the call
+			helper := gen.getUnpackMemoryStringHelper(cgoSpec)
+			gen.submitHelper(helper)
+			fromProxy = fmt.Sprintf("%s(%s)", helper.Name, refName)
+		}
+		switch {
+		case ownTip == tl.TipOwn, ownTip == tl.TipBorrow:
+			// the memory allocated for the call is released right after it
+			allocs = name + "AllocMap"
+		case len(ownTip.FreeWith()) > 0:
+			if release, ok := gen.releaseCall(&tl.CDecl{Name: funcName, Pos: param.Pos}, ownTip,
+				fmt.Sprintf("unsafe.Pointer(%s)", name)); ok {
+				releaseDecl := fmt.Sprintf("if %s != nil {\n%s\n}\n", name, release)
+				to = append(to, proxyDecl{Name: name, Decl: releaseDecl})
+			}
+		}
 		if nillable {
 			fmt.Fprintf(fromBuf, "var %s %s\n", name, cgoSpec)
-			fmt.Fprintf(fromBuf, "if %s != nil {\n%s, _ = %s\n}", refName, name, fromProxy)
+			if allocs != "_" {
+				fmt.Fprintf(fromBuf, "var %s *cgoAllocMap\n", allocs)
+			}
+			fmt.Fprintf(fromBuf, "if %s != nil {\n%s, %s = %s\n}", refName, name, allocs, fromProxy)
 		} else {
-			fmt.Fprintf(fromBuf, "%s, _ := %s", name, fromProxy)
+			fmt.Fprintf(fromBuf, "%s, %s := %s", name, allocs, fromProxy)
 		}
 		from[i] = proxyDecl{Name: name, Decl: fromBuf.String()}
+		if allocs != "_" {
+			freeDecl := fmt.Sprintf("%s.Free()\n", allocs)
+			if nillable {
+				freeDecl = fmt.Sprintf("if %s != nil {\n%s.Free()\n}\n", allocs, allocs)
+			}
+			to = append(to, proxyDecl{Name: allocs, Decl: freeDecl})
+		}
 		if needKeepalive {
 			keepaliveDecl := fmt.Sprintf("runtime.KeepAlive(%s)\n", refName)
 			to = append(to, proxyDecl{Name: refName, Decl: keepaliveDecl})
@@ -1192,6 +1287,8 @@ func (gen *Generator) createProxies(funcName string, funcSpec tl.CType) (from, t
 
 		isPlain := goSpec.IsPlain() || goSpec.IsPlainKind()
 		switch {
+		case arena && isArenaArg(argTip, ownTip, goSpec) && goSpec.Base == "string":
+			// the re-packed strings would be backed by the arena memory reused after Reset
 		case !isPlain && (goSpec.Slices > 0 || len(goSpec.OuterArr) > 0), // ex: []string
 			isPlain && goSpec.Slices > 0 && len(goSpec.OuterArr) > 0, // ex: [4][]byte
 			isPlain && goSpec.Slices > 1:                             // ex: [][]byte
@@ -1214,11 +1311,13 @@ func (gen *Generator) submitHelper(h *Helper) {
 	if h == nil {
 		return
 	}
+	h = gen.helperFor(h)
 	gen.helpersChan <- h
 	reqs := h.Requires
 	for len(reqs) > 0 {
 		var newReqs Helpers
 		for _, req := range reqs {
+			req = gen.helperFor(req)
 			gen.helpersChan <- req
 			newReqs = append(newReqs, req.Requires...)
 		}
@@ -1226,10 +1325,13 @@ func (gen *Generator) submitHelper(h *Helper) {
 	}
 }
 
-func (gen *Generator) writeFunctionBody(wr io.Writer, decl *tl.CDecl) {
+func (gen *Generator) writeFunctionBody(wr io.Writer, decl *tl.CDecl, goName []byte, owned *ownedResult, arena bool) {
 	writeStartFuncBody(wr)
+	if arena {
+		gen.writeArenaFallback(wr, decl, goName)
+	}
 	wr2 := new(reverseBuffer)
-	from, to := gen.createProxies(decl.Name, decl.Spec)
+	from, to := gen.createProxies(decl.Name, decl.Spec, arena)
 	for _, proxy := range from {
 		fmt.Fprintln(wr, proxy.Decl)
 	}
@@ -1253,7 +1355,14 @@ func (gen *Generator) writeFunctionBody(wr io.Writer, decl *tl.CDecl) {
 	writeSpace(wr, 1)
 	// wr2 being populated above
 	wr2.WriteTo(wr)
-	if spec.Return != nil {
+	// the owned values are kept alive until the result that may point into them is converted
+	var keepAlive string
+	for _, name := range gen.ownedParams(decl) {
+		keepAlive += fmt.Sprintf("runtime.KeepAlive(%s)\n", name)
+	}
+	if spec.Return == nil {
+		fmt.Fprint(wr, keepAlive)
+	} else {
 		ptrTipRx, typeTipRx, memTipRx := gen.tr.TipRxsForSpec(tl.TipScopeFunction, decl.Name, decl.Spec)
 		ptrTip := ptrTipRx.Self()
 		typeTip := typeTipRx.Self()
@@ -1261,25 +1370,46 @@ func (gen *Generator) writeFunctionBody(wr io.Writer, decl *tl.CDecl) {
 			// defaults to ref for the returns
 			ptrTip = tl.TipPtrRef
 		}
+		memTip := memTipRx.Self()
+		if !memTip.IsValid() {
+			memTip = gen.MemTipOf(&tl.CDecl{Spec: spec.Return})
+		}
 		goSpec := gen.tr.TranslateSpec((*spec).Return, ptrTip, typeTip)
 		cgoSpec := gen.tr.CGoSpec((*spec).Return, false)
 
 		if goSpec.Base == "string" && goSpec.Pointers == 1 {
-			retProxy, nillable := gen.proxyRetToGo(wr, decl, memTipRx.Self(), "__v", "*__ret", goSpec, cgoSpec)
+			retProxy, nillable := gen.proxyRetToGo(wr, decl, memTip, "__v", "*__ret", goSpec, cgoSpec)
 			if nillable {
 				fmt.Fprintln(wr, "if ret == nil {\nreturn nil\n}")
 			}
 
 			fmt.Fprintln(wr, retProxy)
+			fmt.Fprint(wr, keepAlive)
 			fmt.Fprintln(wr, "return &__v")
+		} else if owned != nil && len(owned.goType) == 0 {
+			// the string is copied into Go memory before the C memory is released
+			fmt.Fprintln(wr, "__v := C.GoString((*C.char)(unsafe.Pointer(__ret)))")
+			fmt.Fprintf(wr, "if __ret != nil {\n%s\n}\n", owned.release)
+			fmt.Fprint(wr, keepAlive)
+			fmt.Fprintln(wr, "return __v")
+		} else if owned == nil && gen.isBorrowedString(decl, goSpec) {
+			// the string is copied into Go memory while the C memory it borrows is alive
+			fmt.Fprintln(wr, "__v := C.GoString((*C.char)(unsafe.Pointer(__ret)))")
+			fmt.Fprint(wr, keepAlive)
+			fmt.Fprintln(wr, "return __v")
 		} else {
-			retProxy, nillable := gen.proxyRetToGo(wr, decl, memTipRx.Self(), "__v", "__ret", goSpec, cgoSpec)
+			retProxy, nillable := gen.proxyRetToGo(wr, decl, memTip, "__v", "__ret", goSpec, cgoSpec)
 			if nillable {
 				fmt.Fprintln(wr, "if ret == nil {\nreturn nil\n}")
 			}
 
 			fmt.Fprintln(wr, retProxy)
-			fmt.Fprintln(wr, "return __v")
+			fmt.Fprint(wr, keepAlive)
+			if owned != nil {
+				fmt.Fprintf(wr, "return new%s(__v, func() {\n%s\n})\n", owned.goType[1:], owned.release)
+			} else {
+				fmt.Fprintln(wr, "return __v")
+			}
 		}
 	}
 	writeEndFuncBody(wr)
@@ -1342,70 +1472,7 @@ var (
 	}
 	cgoAllocMap = &Helper{
 		Name:        "cgoAllocMap",
-		Description: "cgoAllocMap stores pointers to C allocated memory for future reference.",
-		Source: `type cgoAllocMap struct {
-			mux sync.RWMutex
-			m   map[unsafe.Pointer]struct{}
-		}
-
-		var cgoAllocsUnknown = new(cgoAllocMap)
-		var allocReferenceCount int
-
-		func init() {
-			allocReferenceCount = 0
-		}
-
-		func (a *cgoAllocMap) Add(ptr unsafe.Pointer) {
-			a.mux.Lock()
-			defer a.mux.Unlock()
-
-			if a.m == nil {
-				a.m = make(map[unsafe.Pointer]struct{})
-			}
-			a.m[ptr] = struct{}{}
-
-			allocReferenceCount++
-			fmt.Printf("INFO: MEMORY: [PTR %p] CGO memory alloc\n", ptr)
-		}
-
-		func (a *cgoAllocMap) IsEmpty() bool {
-			a.mux.RLock()
-			defer a.mux.RUnlock()
-
-			isEmpty := len(a.m) == 0
-			return isEmpty
-		}
-
-		func (a *cgoAllocMap) Borrow(b *cgoAllocMap) {
-			if b == nil || b.IsEmpty() {
-				return
-			}
-
-			b.mux.Lock()
-			defer b.mux.Unlock()
-			a.mux.Lock()
-			defer a.mux.Unlock()
-
-			for ptr := range b.m {
-				if a.m == nil {
-					a.m = make(map[unsafe.Pointer]struct{})
-				}
-				a.m[ptr] = struct{}{}
-				delete(b.m, ptr)
-			}
-		}
-
-		func (a *cgoAllocMap) Free() {
-			a.mux.Lock()
-			defer a.mux.Unlock()
-
-			for ptr := range a.m {
-				C.free(ptr)
-				delete(a.m, ptr)
-
-				allocReferenceCount--
-				fmt.Printf("INFO: MEMORY: [PTR %p] CGO memory free\n", ptr)
-			}
-		}`,
+		Description: "cgoAllocMap stores pointers to C allocated memory and its sizes for future reference.",
+		Source:      cgoAllocMapSource(false),
 	}
 )
@@ -21,6 +21,7 @@ func (gen *Generator) getCallbackHelpers(goFuncName, cFuncName string, spec tl.C
 	cbCName := fmt.Sprintf("%s_%2x", cFuncName, crc)
 	cbGoName := fmt.Sprintf("%s%2X", unexportName(goFuncName), crc)
 	funcSpec := spec.(*tl.CFunctionSpec)
+	handleIdx, hasHandle := gen.callbackHandleParam(cFuncName, funcSpec)
 
 	var params []string
 	var paramNames []string
@@ -36,6 +37,10 @@ func (gen *Generator) getCallbackHelpers(goFuncName, cFuncName string, spec tl.C
 		paramNamesGo = append(paramNamesGo, fmt.Sprintf("%s%2x", goName, crc))
 	}
 	paramList := strings.Join(params, ", ")
+	var handle string
+	if hasHandle {
+		handle = "c" + string(checkName(gen.tr.TransformName(tl.TargetType, paramNames[handleIdx], false)))
+	}
 	paramNamesList := strings.Join(paramNames, ", ")
 	paramNamesGoList := strings.Join(paramNamesGo, ", ")
 
@@ -69,17 +74,24 @@ func (gen *Generator) getCallbackHelpers(goFuncName, cFuncName string, spec tl.C
 	cgoSpec := gen.tr.CGoSpec(&tl.CTypeSpec{
 		Base: cFuncName,
 	}, true)
+	// a callback with a handle is looked up in the registry upon call,
+	// otherwise the only Go func that can be passed is kept globally.
+	var setFunc string
+	if !hasHandle {
+		setFunc = fmt.Sprintf(`if %sFunc == nil {
+			%sFunc = x
+		}
+		`, cbGoName, cbGoName)
+	}
+
 	buf = new(bytes.Buffer)
 	fmt.Fprintf(buf, "func (x %s) passRef() (ref *%s, allocs *cgoAllocMap)", goFuncName, cgoSpec)
 	fmt.Fprintf(buf, `{
 		if x == nil {
 			return nil, nil
 		}
-		if %sFunc == nil {
-			%sFunc = x
-		}
-		return (*%s)(C.%s), nil
-	}`, cbGoName, cbGoName, cgoSpec, cbCName)
+		%sreturn (*%s)(C.%s), nil
+	}`, setFunc, cgoSpec, cbCName)
 	helpers = append(helpers, &Helper{
 		Name:   fmt.Sprintf("%s.passRef", goFuncName),
 		Source: buf.String(),
@@ -92,17 +104,29 @@ func (gen *Generator) getCallbackHelpers(goFuncName, cFuncName string, spec tl.C
 		if x == nil {
 			return nil, nil
 		}
-		if %sFunc == nil {
-			%sFunc = x
-		}
-		return (%s)(C.%s), nil
-	}`, cbGoName, cbGoName, cgoSpec, cbCName)
+		%sreturn (%s)(C.%s), nil
+	}`, setFunc, cgoSpec, cbCName)
 		helpers = append(helpers, &Helper{
 			Name:   fmt.Sprintf("%s.passValue", goFuncName),
 			Source: buf.String(),
 		})
 	}
 
+	if hasHandle {
+		gen.submitHelper(cgoCallbacks)
+		buf = new(bytes.Buffer)
+		fmt.Fprintf(buf, "func (x %s) Register() unsafe.Pointer", goFuncName)
+		fmt.Fprint(buf, `{
+			return registerCallback(x)
+		}`)
+		helpers = append(helpers, &Helper{
+			Name: fmt.Sprintf("%s.Register", goFuncName),
+			Description: fmt.Sprintf("Register adds the callback to the registry and returns its handle, the handle must be passed\n"+
+				"to C as %s param of the callback and released using UnregisterCallback when not needed.", paramNames[handleIdx]),
+			Source: buf.String(),
+		})
+	}
+
 	buf = new(bytes.Buffer)
 	fmt.Fprintf(buf, "func new%sRef(ref unsafe.Pointer) *%s", goFuncName, goFuncName)
 	fmt.Fprintf(buf, `{
@@ -128,7 +152,11 @@ func (gen *Generator) getCallbackHelpers(goFuncName, cFuncName string, spec tl.C
 
 	gen.writeCallbackProxyFunc(buf, cbGoDecl)
 	fmt.Fprintln(buf, "{")
-	fmt.Fprintf(buf, "if %sFunc != nil {\n", cbGoName)
+	if hasHandle {
+		fmt.Fprintf(buf, "if %sFunc, ok := lookupCallback(unsafe.Pointer(%s)).(%s); ok {\n", cbGoName, handle, goFuncName)
+	} else {
+		fmt.Fprintf(buf, "if %sFunc != nil {\n", cbGoName)
+	}
 	buf.WriteString(proxySrc.String())
 	if funcSpec.Return != nil {
 		ret := fmt.Sprintf("ret%2x", crc)
@@ -144,10 +172,14 @@ func (gen *Generator) getCallbackHelpers(goFuncName, cFuncName string, spec tl.C
 		fmt.Fprintln(buf, "return")
 	}
 	fmt.Fprintln(buf, "}")
-	fmt.Fprintln(buf, `panic("callback func has not been set (race?)")`)
-	fmt.Fprintln(buf, "}")
-
-	fmt.Fprintf(buf, "\n\nvar %sFunc %s", cbGoName, goFuncName)
+	if hasHandle {
+		fmt.Fprintln(buf, `panic("callback func has not been registered")`)
+		fmt.Fprintln(buf, "}")
+	} else {
+		fmt.Fprintln(buf, `panic("callback func has not been set (race?)")`)
+		fmt.Fprintln(buf, "}")
+		fmt.Fprintf(buf, "\n\nvar %sFunc %s", cbGoName, goFuncName)
+	}
 	helpers = append(helpers, &Helper{
 		Name:   cbGoName,
 		Source: buf.String(),
@@ -155,6 +187,108 @@ func (gen *Generator) getCallbackHelpers(goFuncName, cFuncName string, spec tl.C
 	return
 }
 
+// callbackHandleParam returns the index of the callback param that carries the registry handle.
+// The handle tip is set per function on the user data param of a function that accepts the callback,
+// the callback param of the same name, or the only pointer param otherwise, is the one C passes back.
+func (gen *Generator) callbackHandleParam(cFuncName string, funcSpec *tl.CFunctionSpec) (int, bool) {
+	for _, decl := range gen.tr.Declares() {
+		spec, ok := decl.Spec.(*tl.CFunctionSpec)
+		if !ok || !acceptsCallback(spec, cFuncName) {
+			continue
+		}
+		ptrTipRx, ok := gen.tr.PtrTipRx(tl.TipScopeFunction, decl.Name)
+		if !ok {
+			continue
+		}
+		for i, param := range spec.Params {
+			if ptrTipRx.TipAt(i) != tl.TipPtrHandle || !isPointerParam(param) {
+				continue
+			}
+			if idx, ok := matchHandleParam(funcSpec, param.Name); ok {
+				return idx, true
+			}
+		}
+	}
+	return -1, false
+}
+
+// acceptsCallback reports whether one of the function params has the callback type.
+func acceptsCallback(spec *tl.CFunctionSpec, cFuncName string) bool {
+	for _, param := range spec.Params {
+		if param.Spec.GetBase() == cFuncName {
+			return true
+		}
+		if fnSpec, ok := param.Spec.(*tl.CFunctionSpec); ok && fnSpec.Typedef == cFuncName {
+			return true
+		}
+	}
+	return false
+}
+
+func isPointerParam(param *tl.CDecl) bool {
+	return param.Spec.GetPointers() > 0 || param.Spec.GetBase() == "void*"
+}
+
+func matchHandleParam(funcSpec *tl.CFunctionSpec, name string) (int, bool) {
+	idx := -1
+	for i, param := range funcSpec.Params {
+		if !isPointerParam(param) {
+			continue
+		}
+		if len(name) > 0 && param.Name == name {
+			return i, true
+		}
+		if idx >= 0 {
+			// ambiguous, several pointer params and none is named as the user data
+			idx = -2
+			continue
+		}
+		if idx == -1 {
+			idx = i
+		}
+	}
+	return idx, idx >= 0
+}
+
+var cgoCallbacks = &Helper{
+	Name: "cgoCallbacks",
+	Description: "cgoCallbacks keeps the Go callbacks that can be called from C, indexed by their handles.\n" +
+		"A handle is a unique C pointer, so it can be passed as the user data through C code.",
+	Source: `var cgoCallbacks = struct {
+		sync.RWMutex
+		m map[unsafe.Pointer]interface{}
+	}{
+		m: make(map[unsafe.Pointer]interface{}),
+	}
+
+	func registerCallback(fn interface{}) unsafe.Pointer {
+		handle := C.malloc(1)
+		cgoCallbacks.Lock()
+		cgoCallbacks.m[handle] = fn
+		cgoCallbacks.Unlock()
+		return handle
+	}
+
+	func lookupCallback(handle unsafe.Pointer) interface{} {
+		cgoCallbacks.RLock()
+		fn := cgoCallbacks.m[handle]
+		cgoCallbacks.RUnlock()
+		return fn
+	}
+
+	// UnregisterCallback removes the callback from the registry and releases its handle,
+	// C code must not invoke the callback with this handle afterwards.
+	func UnregisterCallback(handle unsafe.Pointer) {
+		cgoCallbacks.Lock()
+		_, ok := cgoCallbacks.m[handle]
+		delete(cgoCallbacks.m, handle)
+		cgoCallbacks.Unlock()
+		if ok {
+			C.free(handle)
+		}
+	}`,
+}
+
 func (gen *Generator) writeCallbackProxyFunc(wr io.Writer, decl *tl.CDecl) {
 	var returnRef string
 	funcSpec := decl.Spec.(*tl.CFunctionSpec)
@@ -250,7 +384,7 @@ func (gen *Generator) proxyCallbackArgToGo(memTip tl.Tip, varName, ptrName strin
 	}
 	gen.submitHelper(cgoAllocMap)
 
-	isPlain := (memTip == tl.TipMemRaw) || goSpec.IsPlain() || goSpec.IsPlainKind()
+	isPlain := memTip.HasCLayout() || goSpec.IsPlain() || goSpec.IsPlainKind()
 	switch {
 	case !isPlain && (goSpec.Slices > 0 || len(goSpec.OuterArr) > 0), // ex: []string
 		isPlain && goSpec.Slices > 0 && len(goSpec.OuterArr) > 0, // ex: [4][]byte
@@ -31,7 +31,7 @@ func (gen *Generator) writeStructMembers(wr io.Writer, structName string, spec t
 		if !memTip.IsValid() {
 			memTip = gen.MemTipOf(member)
 		}
-		if memTip == tl.TipMemRaw {
+		if memTip.HasCLayout() {
 			ptrTip = tl.TipPtrSRef
 		}
 		// declName := checkName(gen.tr.TransformName(tl.TargetType, member.Name, public))
@@ -82,6 +82,12 @@ func (gen *Generator) writeStructMembers(wr io.Writer, structName string, spec t
 func (gen *Generator) writeStructMembersEx(wr io.Writer, structName string, spec tl.CType) {
 	structSpec := spec.(*tl.CStructSpec)
 	ptrTipRx, typeTipRx, memTipRx := gen.tr.TipRxsForSpec(tl.TipScopeType, structName, structSpec)
+	// with bit-fields the Go struct is laid out as the C one, the padding is explicit
+	storages := bitStoragesOf(structSpec)
+	var offset int
+	if len(storages) > 0 {
+		gen.writeBitFieldAlign(wr, structSpec)
+	}
 	const public = true
 	for i, member := range structSpec.Members {
 		ptrTip := ptrTipRx.TipAt(i)
@@ -96,11 +102,29 @@ func (gen *Generator) writeStructMembersEx(wr io.Writer, structName string, spec
 		if !memTip.IsValid() {
 			memTip = gen.MemTipOf(member)
 		}
-		if memTip == tl.TipMemRaw {
+		if memTip.HasCLayout() {
 			ptrTip = tl.TipPtrSRef
 		}
+		if member.IsBitField() {
+			// bit-fields share the bytes that are accessed by the generated methods
+			if st, ok := storages[member]; ok {
+				if st.offset > offset {
+					fmt.Fprintf(wr, "_ [%d]byte\n", st.offset-offset)
+				}
+				fmt.Fprintf(wr, "%s [%d]byte\n", st.name(), st.size)
+				offset = st.offset + st.size
+			}
+			continue
+		}
+		if len(storages) > 0 {
+			if member.Offset > offset {
+				fmt.Fprintf(wr, "_ [%d]byte\n", member.Offset-offset)
+			}
+			offset = member.Offset + member.Size
+		}
 		declName := checkName(gen.tr.TransformName(tl.TargetType, member.Name, public))
 		// declNameL := unexportName(string(declName))
+		gen.writeDocComment(wr, member.Doc, false)
 
 		switch member.Spec.Kind() {
 		case tl.TypeKind:
@@ -155,6 +179,9 @@ func (gen *Generator) writeStructMembersEx(wr io.Writer, structName string, spec
 		}
 		writeSpace(wr, 1)
 	}
+	if len(storages) > 0 && structSpec.Size > offset {
+		fmt.Fprintf(wr, "_ [%d]byte\n", structSpec.Size-offset)
+	}
 
 	if memTipRx.Self() == tl.TipMemRaw {
 		return
@@ -227,7 +254,9 @@ func (gen *Generator) writeFunctionParam(wr io.Writer, param *tl.CDecl, ptrTip t
 	}
 }
 
-func (gen *Generator) writeFunctionParams(wr io.Writer, funcName string, funcSpec tl.CType) {
+// writeFunctionParams writes the params of the function, with owned set the pointers
+// to the types owned by Go are taken as the wrappers holding those.
+func (gen *Generator) writeFunctionParams(wr io.Writer, funcName string, funcSpec tl.CType, owned bool) {
 	spec := funcSpec.(*tl.CFunctionSpec)
 	ptrTipSpecRx, _ := gen.tr.PtrTipRx(tl.TipScopeFunction, funcName)
 	typeTipSpecRx, _ := gen.tr.TypeTipRx(tl.TipScopeFunction, funcName)
@@ -254,7 +283,13 @@ func (gen *Generator) writeFunctionParams(wr io.Writer, funcName string, funcSpe
 			}
 		}
 
-		gen.writeFunctionParam(wr, param, ptrTip, typeTip)
+		if typeName, ok := gen.ownedTypeOf(gen.tr.TranslateSpec(param.Spec, ptrTip, typeTip)); owned && ok {
+			const public = false
+			declName := checkName(gen.tr.TransformName(tl.TargetType, param.Name, public))
+			fmt.Fprintf(wr, "%s *%s", declName, typeName)
+		} else {
+			gen.writeFunctionParam(wr, param, ptrTip, typeTip)
+		}
 
 		if i < len(spec.Params)-1 && ptrTipSpecRx.TipAt(i+1) != tl.TipPtrInst {
 			fmt.Fprintf(wr, ", ")
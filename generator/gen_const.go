@@ -9,7 +9,7 @@ import (
 	tl "github.com/xlab/c-for-go/translator"
 )
 
-func (gen *Generator) writeDefinesGroup(wr io.Writer, defines []*tl.CDecl) (n int) {
+func (gen *Generator) writeDefinesGroup(wr io.Writer, defines []*tl.CDecl, goType string) (n int) {
 	writeStartConst(wr)
 	for _, decl := range defines {
 		if !decl.IsDefine {
@@ -19,9 +19,13 @@ func (gen *Generator) writeDefinesGroup(wr io.Writer, defines []*tl.CDecl) (n in
 		if decl.Value == nil && string(name) == decl.Expression {
 			continue
 		}
+		gen.writeDocComment(wr, decl.Doc, true)
 		fmt.Fprintf(wr, "// %s as defined in %s\n", name,
 			filepath.ToSlash(gen.tr.SrcLocation(tl.TargetConst, decl.Name, decl.Pos)))
 
+		if len(goType) > 0 {
+			name = append(append(name, ' '), goType...)
+		}
 		if decl.Value != nil {
 			fmt.Fprintf(wr, "%s = %v", name, decl.Value)
 		} else if len(decl.Expression) > 0 {
@@ -37,11 +41,62 @@ func (gen *Generator) writeDefinesGroup(wr io.Writer, defines []*tl.CDecl) (n in
 	return
 }
 
+// groupDefines splits the defines into the untyped ones and the groups of typed ones
+// keyed by the cgo type name, the keys are returned in the order of appearance.
+func groupDefines(defines []*tl.CDecl) (untyped []*tl.CDecl, typed map[string][]*tl.CDecl, typeNames []string) {
+	typed = make(map[string][]*tl.CDecl)
+	for _, decl := range defines {
+		if decl.Spec == nil {
+			untyped = append(untyped, decl)
+			continue
+		}
+		typeName := decl.Spec.CGoName()
+		if _, ok := typed[typeName]; !ok {
+			typeNames = append(typeNames, typeName)
+		}
+		typed[typeName] = append(typed[typeName], decl)
+	}
+	return
+}
+
+// writeTypedDefines writes the group of defines typed with the named C type, it's called
+// next to the type declaration so the constants are found along with their type.
+func (gen *Generator) writeTypedDefines(wr io.Writer, typeName string) int {
+	if gen.typedDefinesSeen[typeName] {
+		return 0
+	}
+	_, typed, _ := groupDefines(gen.tr.Defines())
+	defines := typed[typeName]
+	if len(defines) == 0 {
+		return 0
+	}
+	gen.typedDefinesSeen[typeName] = true
+	goType := gen.tr.TranslateSpec(defines[0].Spec).String()
+	writeSpace(wr, 1)
+	return gen.writeDefinesGroup(wr, defines, goType)
+}
+
+// hasTypeDecl checks if the named C type is declared in Go by WriteTypedefs or WriteConst,
+// so the typed defines can be written next to it.
+func (gen *Generator) hasTypeDecl(typeName string) bool {
+	for _, decl := range gen.tr.Typedefs() {
+		if decl.Name != typeName {
+			continue
+		}
+		switch decl.Spec.Kind() {
+		case tl.TypeKind, tl.EnumKind:
+			return gen.tr.IsAcceptableName(tl.TargetType, decl.Name)
+		}
+	}
+	return false
+}
+
 func (gen *Generator) writeConstDeclaration(wr io.Writer, decl *tl.CDecl) {
 	declName := gen.tr.TransformName(tl.TargetConst, decl.Name)
 	if decl.Value == nil && string(declName) == decl.Expression {
 		return
 	}
+	gen.writeDocComment(wr, decl.Doc, true)
 	fmt.Fprintf(wr, "// %s as declared in %s\n", declName,
 		filepath.ToSlash(gen.tr.SrcLocation(tl.TargetConst, decl.Name, decl.Pos)))
 	goSpec := gen.tr.TranslateSpec(decl.Spec)
@@ -69,6 +124,7 @@ func (gen *Generator) expandEnumAnonymous(wr io.Writer, decl *tl.CDecl, namesSee
 	spec := decl.Spec.(*tl.CEnumSpec)
 	if hasType {
 		enumType := gen.tr.TranslateSpec(&spec.Type)
+		gen.writeDocComment(wr, decl.Doc, true)
 		fmt.Fprintf(wr, "// %s as declared in %s\n", typeName,
 			filepath.ToSlash(gen.tr.SrcLocation(tl.TargetConst, decl.Name, decl.Pos)))
 		fmt.Fprintf(wr, "type %s %s\n", typeName, enumType)
@@ -77,18 +133,24 @@ func (gen *Generator) expandEnumAnonymous(wr io.Writer, decl *tl.CDecl, namesSee
 			filepath.ToSlash(gen.tr.SrcLocation(tl.TargetConst, decl.Name, decl.Pos)))
 	}
 	writeStartConst(wr)
+	var members []*tl.CDecl
 	for i, m := range spec.Members {
 		if !gen.tr.IsAcceptableName(tl.TargetConst, m.Name) {
+			gen.reportFiltered(m, m.Name)
 			continue
 		}
 		mName := gen.tr.TransformName(tl.TargetConst, m.Name)
 		if len(mName) == 0 {
 			continue
 		} else if namesSeen[string(mName)] {
+			gen.tr.Diagnostics().Add(tl.DiagSkipped, m.Pos, m.Name,
+				fmt.Sprintf("Go name %s has already been declared", mName))
 			continue
 		} else {
 			namesSeen[string(mName)] = true
 		}
+		members = append(members, m)
+		gen.writeDocComment(wr, m.Doc, !hasType)
 		if !hasType {
 			fmt.Fprintf(wr, "// %s as declared in %s\n", mName,
 				filepath.ToSlash(gen.tr.SrcLocation(tl.TargetConst, m.Name, m.Pos)))
@@ -117,6 +179,10 @@ func (gen *Generator) expandEnumAnonymous(wr io.Writer, decl *tl.CDecl, namesSee
 		}
 	}
 	writeEndConst(wr)
+	if hasType {
+		gen.writeTypedDefines(wr, decl.Name)
+		gen.writeEnumMethods(wr, decl.Name, typeName, members)
+	}
 	writeSpace(wr, 1)
 }
 
@@ -132,6 +198,7 @@ func (gen *Generator) expandEnum(wr io.Writer, decl *tl.CDecl, namesSeen map[str
 	spec := decl.Spec.(*tl.CEnumSpec)
 	tagName := gen.tr.TransformName(tl.TargetType, decl.Spec.GetBase())
 	enumType := gen.tr.TranslateSpec(&spec.Type)
+	gen.writeDocComment(wr, decl.Doc, true)
 	fmt.Fprintf(wr, "// %s as declared in %s\n", tagName,
 		filepath.ToSlash(gen.tr.SrcLocation(tl.TargetConst, decl.Name, decl.Pos)))
 	fmt.Fprintf(wr, "type %s %s\n", tagName, enumType)
@@ -149,18 +216,24 @@ func (gen *Generator) expandEnum(wr io.Writer, decl *tl.CDecl, namesSeen map[str
 	fmt.Fprintf(wr, "// %s enumeration from %s\n", tagName,
 		filepath.ToSlash(gen.tr.SrcLocation(tl.TargetConst, decl.Name, decl.Pos)))
 	writeStartConst(wr)
+	var members []*tl.CDecl
 	for i, m := range spec.Members {
 		if !gen.tr.IsAcceptableName(tl.TargetConst, m.Name) {
+			gen.reportFiltered(m, m.Name)
 			continue
 		}
 		mName := gen.tr.TransformName(tl.TargetConst, m.Name)
 		if len(mName) == 0 {
 			continue
 		} else if namesSeen[string(mName)] {
+			gen.tr.Diagnostics().Add(tl.DiagSkipped, m.Pos, m.Name,
+				fmt.Sprintf("Go name %s has already been declared", mName))
 			continue
 		} else {
 			namesSeen[string(mName)] = true
 		}
+		members = append(members, m)
+		gen.writeDocComment(wr, m.Doc, false)
 		switch {
 		case m.Value != nil:
 			fmt.Fprintf(wr, "%s %s = %v\n", mName, declName, iotaOnZero(i, m.Value))
@@ -175,6 +248,12 @@ func (gen *Generator) expandEnum(wr io.Writer, decl *tl.CDecl, namesSeen map[str
 		}
 	}
 	writeEndConst(wr)
+	if isTypedef {
+		gen.writeTypedDefines(wr, decl.Name)
+		gen.writeEnumMethods(wr, decl.Name, declName, members)
+	} else {
+		gen.writeEnumMethods(wr, decl.Spec.GetBase(), tagName, members)
+	}
 	writeSpace(wr, 1)
 }
 
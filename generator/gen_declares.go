@@ -63,16 +63,19 @@ func (gen *Generator) writeFunctionAsArg(wr io.Writer, decl *tl.CDecl,
 	}
 	goSpec := gen.tr.TranslateSpec(decl.Spec, ptrTip, typeTip)
 	fmt.Fprintf(wr, "%s %s", goName, goSpec)
-	gen.writeFunctionParams(wr, cName, decl.Spec)
+	gen.writeFunctionParams(wr, cName, decl.Spec, false)
 	if len(returnRef) > 0 {
 		fmt.Fprintf(wr, " %s", returnRef)
 	}
 }
 
+// writeFunctionDeclaration writes the Go wrapper of the C function, or its variant
+// among ArenaCalls that allocates the arguments from an arena.
 func (gen *Generator) writeFunctionDeclaration(wr io.Writer, decl *tl.CDecl,
-	ptrTip, typeTip tl.Tip, public bool) {
+	ptrTip, typeTip tl.Tip, public, arena bool) {
 
 	var returnRef string
+	var owned *ownedResult
 	spec := decl.Spec.(*tl.CFunctionSpec)
 	if spec.Return != nil {
 		ret := gen.tr.TranslateSpec(spec.Return, ptrTip, typeTip)
@@ -81,22 +84,32 @@ func (gen *Generator) writeFunctionDeclaration(wr io.Writer, decl *tl.CDecl,
 		// }
 		// ret.Raw = unexportName(ret.Raw)
 		returnRef = ret.String()
+		if owned = gen.ownedResultOf(decl, ret); owned != nil && len(owned.goType) > 0 {
+			returnRef = owned.goType
+		}
 	}
 	cName, _ := getName(decl)
 	goName := checkName(gen.tr.TransformName(tl.TargetFunction, cName, public))
 	if returnRef == string(goName) {
 		goName = gen.tr.TransformName(tl.TargetFunction, "new_"+cName, public)
 	}
-	fmt.Fprintf(wr, "// %s function as declared in %s\n", goName,
-		filepath.ToSlash(gen.tr.SrcLocation(tl.TargetFunction, decl.Name, decl.Pos)))
-	fmt.Fprintf(wr, "func")
-	gen.writeInstanceObjectParam(wr, cName, decl.Spec)
+	if arena {
+		gen.submitHelper(arenaCallsHelper)
+		fmt.Fprintf(wr, "// %s calls %s with the arguments allocated from the arena.\n", goName, decl.Name)
+		fmt.Fprintf(wr, "func (calls ArenaCalls)")
+	} else {
+		gen.writeDocComment(wr, decl.Doc, true)
+		fmt.Fprintf(wr, "// %s function as declared in %s\n", goName,
+			filepath.ToSlash(gen.tr.SrcLocation(tl.TargetFunction, decl.Name, decl.Pos)))
+		fmt.Fprintf(wr, "func")
+		gen.writeInstanceObjectParam(wr, cName, decl.Spec)
+	}
 	fmt.Fprintf(wr, " %s", goName)
-	gen.writeFunctionParams(wr, cName, decl.Spec)
+	gen.writeFunctionParams(wr, cName, decl.Spec, true)
 	if len(returnRef) > 0 {
 		fmt.Fprintf(wr, " %s", returnRef)
 	}
-	gen.writeFunctionBody(wr, decl)
+	gen.writeFunctionBody(wr, decl, goName, owned, arena)
 	writeSpace(wr, 1)
 }
 
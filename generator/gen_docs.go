@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeDocComment writes the doc comment of a C declaration as Go comment lines,
+// an empty comment line separates the doc from the source reference that follows.
+func (gen *Generator) writeDocComment(wr io.Writer, doc string, refFollows bool) {
+	if len(doc) == 0 {
+		return
+	}
+	if gen.cfg.Options.DoxygenDocs {
+		doc = formatDoxygen(doc)
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		if len(line) == 0 {
+			fmt.Fprintln(wr, "//")
+			continue
+		}
+		fmt.Fprintf(wr, "// %s\n", line)
+	}
+	if refFollows {
+		fmt.Fprintln(wr, "//")
+	}
+}
+
+// formatDoxygen rewrites Doxygen commands like @brief, @param and @return into plain text,
+// the params are listed after the description.
+func formatDoxygen(doc string) string {
+	var text, params, returns []string
+	var last *[]string
+	for _, line := range strings.Split(doc, "\n") {
+		cmd, rest := doxygenCommand(line)
+		switch cmd {
+		case "":
+			if len(line) > 0 && last != nil && len(*last) > 0 {
+				// continues the previous command
+				(*last)[len(*last)-1] += " " + line
+				continue
+			}
+			last = nil
+			text = append(text, line)
+		case "brief", "short":
+			last = &text
+			text = append(text, rest)
+		case "param", "param[in]", "param[out]", "param[in,out]":
+			last = &params
+			fields := strings.SplitN(rest, " ", 2)
+			if len(fields) == 2 {
+				params = append(params, fmt.Sprintf("  - %s: %s", fields[0], strings.TrimSpace(fields[1])))
+			} else {
+				params = append(params, fmt.Sprintf("  - %s", fields[0]))
+			}
+		case "return", "returns", "retval":
+			last = &returns
+			returns = append(returns, "Returns: "+rest)
+		default:
+			last = nil
+			text = append(text, strings.ToUpper(cmd[:1])+cmd[1:]+": "+rest)
+		}
+	}
+	out := strings.TrimSpace(strings.Join(text, "\n"))
+	if len(params) > 0 {
+		out += "\n\nParameters:\n" + strings.Join(params, "\n")
+	}
+	if len(returns) > 0 {
+		out += "\n\n" + strings.Join(returns, "\n")
+	}
+	return strings.TrimSpace(out)
+}
+
+// doxygenCommand splits a line that starts with a Doxygen command into the command and its text.
+func doxygenCommand(line string) (cmd, rest string) {
+	if len(line) < 2 || (line[0] != '@' && line[0] != '\\') {
+		return "", line
+	}
+	fields := strings.SplitN(line[1:], " ", 2)
+	if len(fields) == 2 {
+		rest = strings.TrimSpace(fields[1])
+	}
+	return fields[0], rest
+}
@@ -0,0 +1,249 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+	"math/bits"
+	"sort"
+	"strings"
+
+	tl "github.com/xlab/c-for-go/translator"
+)
+
+type enumMember struct {
+	name  []byte
+	cName string
+	value uint64
+	// alias is set for the members sharing a value with a preceding one.
+	alias bool
+}
+
+// enumMembers returns the Go names, the C names and the values of the enum members.
+func (gen *Generator) enumMembers(members []*tl.CDecl) []enumMember {
+	valueMap := gen.tr.ValueMap()
+	seen := make(map[uint64]bool, len(members))
+	list := make([]enumMember, 0, len(members))
+	for _, m := range members {
+		value, ok := enumValue(valueMap[m.Name])
+		if !ok {
+			continue
+		}
+		list = append(list, enumMember{
+			name:  gen.tr.TransformName(tl.TargetConst, m.Name),
+			cName: m.Name,
+			value: value,
+			alias: seen[value],
+		})
+		seen[value] = true
+	}
+	return list
+}
+
+func enumValue(v tl.Value) (uint64, bool) {
+	switch v := v.(type) {
+	case int32:
+		return uint64(v), true
+	case uint32:
+		return uint64(v), true
+	case int64:
+		return uint64(v), true
+	case uint64:
+		return v, true
+	case int:
+		return uint64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// isFlagEnum checks if the enum is listed in FlagEnums or looks like a set of bit-flags:
+// all the non-zero values are powers of two and at least one of them is greater than 2,
+// so the plain enumerations like 0, 1, 2 are not taken for flags.
+func (gen *Generator) isFlagEnum(cName string, members []enumMember) bool {
+	for _, rx := range gen.flagEnumRxs {
+		if rx.MatchString(cName) {
+			return true
+		}
+	}
+	var hasHighBit bool
+	for _, m := range members {
+		if m.value&(m.value-1) != 0 {
+			return false
+		} else if m.value > 2 {
+			hasHighBit = true
+		}
+	}
+	return hasHighBit
+}
+
+// writeEnumMethods writes the methods of the Go type of an enum, the members
+// are the constants that have been written for it.
+func (gen *Generator) writeEnumMethods(wr io.Writer, cName string, typeName []byte, members []*tl.CDecl) {
+	opts := gen.cfg.Options
+	if len(typeName) == 0 || !(opts.EnumStringers || opts.EnumHelpers) {
+		return
+	}
+	list := gen.enumMembers(members)
+	if len(list) == 0 {
+		return
+	}
+	isFlags := gen.isFlagEnum(cName, list)
+	if opts.EnumStringers {
+		writeSpace(wr, 1)
+		if isFlags {
+			gen.writeFlagEnumMethods(wr, typeName, list)
+		} else {
+			gen.writeEnumStringer(wr, typeName, list)
+		}
+	}
+	if opts.EnumHelpers {
+		writeSpace(wr, 1)
+		gen.writeEnumHelpers(wr, typeName, list, isFlags)
+	}
+}
+
+func (gen *Generator) writeEnumStringer(wr io.Writer, typeName []byte, list []enumMember) {
+	fmt.Fprintf(wr, "// String returns the name of the %s value.\n", typeName)
+	fmt.Fprintf(wr, "func (e %s) String() string", typeName)
+	writeStartFuncBody(wr)
+	fmt.Fprintln(wr, "switch e {")
+	for _, m := range list {
+		if m.alias {
+			continue
+		}
+		fmt.Fprintf(wr, "case %s:\n", m.name)
+		fmt.Fprintf(wr, "return %q\n", m.name)
+	}
+	fmt.Fprintln(wr, "}")
+	fmt.Fprintf(wr, "return \"%s(\" + strconv.FormatInt(int64(e), 10) + \")\"\n", typeName)
+	writeEndFuncBody(wr)
+}
+
+// writeEnumHelpers writes IsValid, the list of values and the parser of the names,
+// the C names and the Go names are accepted. A value of the flags enum is valid if
+// it has no unknown bits set, and it's parsed from the names joined with |.
+func (gen *Generator) writeEnumHelpers(wr io.Writer, typeName []byte, list []enumMember, isFlags bool) {
+	var values []string
+	for _, m := range list {
+		if !m.alias {
+			values = append(values, string(m.name))
+		}
+	}
+	fmt.Fprintf(wr, "// IsValid reports whether e is a known %s value.\n", typeName)
+	fmt.Fprintf(wr, "func (e %s) IsValid() bool", typeName)
+	writeStartFuncBody(wr)
+	if isFlags {
+		fmt.Fprintf(wr, "return e&^(%s) == 0\n", strings.Join(values, " | "))
+	} else {
+		fmt.Fprintln(wr, "switch e {")
+		fmt.Fprintf(wr, "case %s:\n", strings.Join(values, ", "))
+		fmt.Fprintln(wr, "return true")
+		fmt.Fprintln(wr, "}")
+		fmt.Fprintln(wr, "return false")
+	}
+	writeEndFuncBody(wr)
+	writeSpace(wr, 1)
+
+	fmt.Fprintf(wr, "// %sValues returns all the known %s values.\n", typeName, typeName)
+	fmt.Fprintf(wr, "func %sValues() []%s", typeName, typeName)
+	writeStartFuncBody(wr)
+	fmt.Fprintf(wr, "return []%s{\n", typeName)
+	for _, value := range values {
+		fmt.Fprintf(wr, "%s,\n", value)
+	}
+	fmt.Fprintln(wr, "}")
+	writeEndFuncBody(wr)
+	writeSpace(wr, 1)
+
+	parseName := fmt.Sprintf("parse%sName", typeName)
+	if isFlags {
+		fmt.Fprintf(wr, "// Parse%s returns the %s value of the flag names joined with |,\n", typeName, typeName)
+		fmt.Fprintf(wr, "// either the C names or the Go names are accepted.\n")
+		fmt.Fprintf(wr, "func Parse%s(s string) (%s, error)", typeName, typeName)
+		writeStartFuncBody(wr)
+		fmt.Fprintln(wr, `if s == "0" {`)
+		fmt.Fprintln(wr, "return 0, nil")
+		fmt.Fprintln(wr, "}")
+		fmt.Fprintf(wr, "var e %s\n", typeName)
+		fmt.Fprintln(wr, `for _, name := range strings.Split(s, "|") {`)
+		fmt.Fprintf(wr, "flag, err := %s(strings.TrimSpace(name))\n", parseName)
+		fmt.Fprintln(wr, "if err != nil {")
+		fmt.Fprintln(wr, "return 0, err")
+		fmt.Fprintln(wr, "}")
+		fmt.Fprintln(wr, "e |= flag")
+		fmt.Fprintln(wr, "}")
+		fmt.Fprintln(wr, "return e, nil")
+		writeEndFuncBody(wr)
+		writeSpace(wr, 1)
+	} else {
+		parseName = fmt.Sprintf("Parse%s", typeName)
+		fmt.Fprintf(wr, "// %s returns the %s value by its name,\n", parseName, typeName)
+		fmt.Fprintf(wr, "// either the C name or the Go name is accepted.\n")
+	}
+	fmt.Fprintf(wr, "func %s(s string) (%s, error)", parseName, typeName)
+	writeStartFuncBody(wr)
+	fmt.Fprintln(wr, "switch s {")
+	seen := make(map[string]bool, 2*len(list))
+	for _, m := range list {
+		names := make([]string, 0, 2)
+		for _, name := range []string{m.cName, string(m.name)} {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, fmt.Sprintf("%q", name))
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		fmt.Fprintf(wr, "case %s:\n", strings.Join(names, ", "))
+		fmt.Fprintf(wr, "return %s, nil\n", m.name)
+	}
+	fmt.Fprintln(wr, "}")
+	fmt.Fprintf(wr, "return 0, fmt.Errorf(\"unknown %s name: %%q\", s)\n", typeName)
+	writeEndFuncBody(wr)
+}
+
+func (gen *Generator) writeFlagEnumMethods(wr io.Writer, typeName []byte, list []enumMember) {
+	fmt.Fprintf(wr, "// Has reports whether all the flags of f are set in e.\n")
+	fmt.Fprintf(wr, "func (e %s) Has(f %s) bool { return e&f == f }\n\n", typeName, typeName)
+	fmt.Fprintf(wr, "// Set returns e with the flags of f set.\n")
+	fmt.Fprintf(wr, "func (e %s) Set(f %s) %s { return e | f }\n\n", typeName, typeName, typeName)
+	fmt.Fprintf(wr, "// Clear returns e with the flags of f cleared.\n")
+	fmt.Fprintf(wr, "func (e %s) Clear(f %s) %s { return e &^ f }\n\n", typeName, typeName, typeName)
+
+	zero := "0"
+	for _, m := range list {
+		if m.value == 0 {
+			zero = string(m.name)
+		}
+	}
+	fmt.Fprintf(wr, "// String returns the names of the flags set in e joined with |,\n")
+	fmt.Fprintf(wr, "// the unknown bits are written as a hex number.\n")
+	fmt.Fprintf(wr, "func (e %s) String() string", typeName)
+	writeStartFuncBody(wr)
+	fmt.Fprintln(wr, "if e == 0 {")
+	fmt.Fprintf(wr, "return %q\n", zero)
+	fmt.Fprintln(wr, "}")
+	fmt.Fprintln(wr, "var names []string")
+	// the composite flags go first, so those are named only when all their bits are set
+	flags := make([]enumMember, 0, len(list))
+	for _, m := range list {
+		if m.value != 0 && !m.alias {
+			flags = append(flags, m)
+		}
+	}
+	sort.SliceStable(flags, func(i, j int) bool {
+		return bits.OnesCount64(flags[i].value) > bits.OnesCount64(flags[j].value)
+	})
+	for _, m := range flags {
+		fmt.Fprintf(wr, "if e&%s == %s {\n", m.name, m.name)
+		fmt.Fprintf(wr, "names = append(names, %q)\n", m.name)
+		fmt.Fprintf(wr, "e &^= %s\n", m.name)
+		fmt.Fprintln(wr, "}")
+	}
+	fmt.Fprintln(wr, "if e != 0 {")
+	fmt.Fprintln(wr, "names = append(names, \"0x\"+strconv.FormatUint(uint64(e), 16))")
+	fmt.Fprintln(wr, "}")
+	fmt.Fprintln(wr, "return strings.Join(names, \"|\")")
+	writeEndFuncBody(wr)
+}
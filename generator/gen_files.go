@@ -76,7 +76,9 @@ func (gen *Generator) writeGoHelpersHeader(wr io.Writer) {
 	writeSpace(wr, 1)
 	writePackageName(wr, gen.pkg)
 	writeSpace(wr, 1)
-	gen.WriteIncludes(wr)
+	if !gen.noCGO {
+		gen.WriteIncludes(wr)
+	}
 }
 
 func (gen *Generator) writeCHHelpersHeader(wr io.Writer) {
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"fmt"
+	"io"
+)
+
+// AllocsDebugTag is the build tag that makes the generated package report the struct
+// wrappers collected by the garbage collector without an explicit Free.
+const AllocsDebugTag = "cforgo_debug"
+
+var trackAllocsHelper = &Helper{
+	Name: "trackAllocs",
+	Description: "trackAllocs releases the allocations made for the C object at ref once the object becomes\n" +
+		"unreachable, the finalizer is disarmed by untrackAllocs that releases them right away.",
+	Source: `func trackAllocs(ref interface{}, name string, allocs *cgoAllocMap) {
+			if allocs == nil || allocs.IsEmpty() {
+				return
+			}
+			ptr := reflect.ValueOf(ref).Pointer()
+			if v, loaded := trackedAllocs.LoadOrStore(ptr, allocs); loaded {
+				if tracked := v.(*cgoAllocMap); tracked != allocs {
+					tracked.Borrow(allocs)
+				}
+				return
+			}
+			runtime.SetFinalizer(ref, func(interface{}) {
+				trackedAllocs.Delete(ptr)
+				if reportUnfreed != nil && !allocs.IsEmpty() {
+					reportUnfreed(name, ptr)
+				}
+				allocs.Free()
+			})
+		}
+
+		// untrackAllocs releases the allocations tracked for the object at ref.
+		func untrackAllocs(ref interface{}) {
+			ptr := reflect.ValueOf(ref).Pointer()
+			v, ok := trackedAllocs.Load(ptr)
+			if !ok {
+				return
+			}
+			trackedAllocs.Delete(ptr)
+			runtime.SetFinalizer(ref, nil)
+			v.(*cgoAllocMap).Free()
+		}
+
+		var trackedAllocs sync.Map
+
+		// reportUnfreed is set by the debug build to report the objects collected without an explicit Free.
+		var reportUnfreed func(name string, ptr uintptr)`,
+	Requires: Helpers{cgoAllocMap},
+}
+
+func (gen *Generator) getTrackAllocsHelper() *Helper {
+	gen.tracksAllocs = true
+	return trackAllocsHelper
+}
+
+// WriteAllocsDebug writes the file built with the debug tag that reports the struct
+// wrappers which allocations were released by the finalizer rather than by Free.
+func (gen *Generator) WriteAllocsDebug(wr io.Writer) int {
+	if !gen.tracksAllocs {
+		return 0
+	}
+	fmt.Fprintf(wr, "//go:build %s\n// +build %s\n\n", AllocsDebugTag, AllocsDebugTag)
+	gen.WritePackageHeader(wr)
+	fmt.Fprintf(wr, `func init() {
+		reportUnfreed = func(name string, ptr uintptr) {
+			fmt.Fprintf(os.Stderr, "%s: %%s at %%#x was collected without an explicit Free\n", name, ptr)
+		}
+	}`, gen.pkg)
+	writeSpace(wr, 1)
+	return 1
+}
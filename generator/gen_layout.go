@@ -0,0 +1,160 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	tl "github.com/xlab/c-for-go/translator"
+)
+
+// writeLayoutConsts writes the size, the alignment and the member offsets of a struct
+// or union as computed for the data model of the target, the compile-time checks of those
+// against the C type, or the Go type that mirrors it without cgo, are written by writeLayoutChecks.
+// Those are written with the LayoutChecks option only.
+func (gen *Generator) writeLayoutConsts(wr io.Writer, goName []byte, spec *tl.CStructSpec) {
+	if !gen.cfg.Options.LayoutChecks || spec.Size <= 0 {
+		return
+	}
+	const public = true
+	type offset struct {
+		name, field string
+		value       int
+	}
+	var offsets []offset
+	for _, m := range gen.layoutMembers(spec) {
+		field := m.Name
+		if gen.noCGO {
+			field = string(gen.tr.TransformName(tl.TargetType, m.Name, public))
+		}
+		offsets = append(offsets, offset{
+			name:  fmt.Sprintf("Offsetof%s%s", goName, gen.tr.TransformName(tl.TargetType, m.Name, public)),
+			field: field,
+			value: m.Offset,
+		})
+	}
+
+	fmt.Fprintf(wr, "// %s layout as computed for the target.\n", goName)
+	fmt.Fprintln(wr, "const (")
+	fmt.Fprintf(wr, "Sizeof%s = %d\n", goName, spec.Size)
+	fmt.Fprintf(wr, "Alignof%s = %d\n", goName, spec.Align)
+	if !spec.IsUnion {
+		for _, o := range offsets {
+			fmt.Fprintf(wr, "%s = %d\n", o.name, o.value)
+		}
+	}
+	fmt.Fprintln(wr, ")")
+	writeSpace(wr, 1)
+
+	value := fmt.Sprintf("%s{}", goName)
+	if !gen.noCGO {
+		value = fmt.Sprintf("C.%s{}", spec.CGoName())
+	}
+	checks := []string{fmt.Sprintf("// %s", goName),
+		fmt.Sprintf("_ = x[Sizeof%s-unsafe.Sizeof(%s)]", goName, value)}
+	if !spec.IsUnion {
+		for _, o := range offsets {
+			checks = append(checks, fmt.Sprintf("_ = x[%s-unsafe.Offsetof(%s.%s)]", o.name, value, o.field))
+		}
+	}
+	gen.layoutChecks = append(gen.layoutChecks, checks...)
+}
+
+// writeLayoutChecks writes the compile-time checks of the layouts collected by writeLayoutConsts.
+func (gen *Generator) writeLayoutChecks(wr io.Writer) {
+	if len(gen.layoutChecks) == 0 {
+		return
+	}
+	fmt.Fprintln(wr, "// An out of bounds index or an overflow of uintptr reported by the compiler")
+	fmt.Fprintln(wr, "// here means that the layout of the type differs from the computed one.")
+	fmt.Fprintf(wr, "func _() {\n")
+	fmt.Fprintln(wr, "var x [1]struct{}")
+	for _, check := range gen.layoutChecks {
+		fmt.Fprintln(wr, check)
+	}
+	writeEndFuncBody(wr)
+	writeSpace(wr, 1)
+	gen.layoutChecks = nil
+}
+
+// layoutMembers returns the struct members which offsets are checked, those are
+// the named members except bit-fields. With cgo the members that cannot be aligned
+// in Go are left out, since cgo doesn't expose them.
+func (gen *Generator) layoutMembers(spec *tl.CStructSpec) []*tl.CDecl {
+	var members []*tl.CDecl
+	for _, m := range spec.Members {
+		if m.IsBitField() || m.Size <= 0 || len(m.Name) == 0 {
+			continue
+		}
+		if !gen.noCGO && m.Offset%gen.goAlignOf(m.Align) != 0 {
+			continue
+		}
+		members = append(members, m)
+	}
+	return members
+}
+
+// writeMirrorStruct writes a Go struct that mirrors the layout of the C struct, unlike the
+// default struct wrappers it's passed to C by a pointer cast, so no copying is involved.
+// The layout is checked against the one cgo has by a generated test.
+func (gen *Generator) writeMirrorStruct(wr io.Writer, decl *tl.CDecl, seenNames map[string]bool) {
+	if !gen.writeLayoutStruct(wr, decl, seenNames) {
+		return
+	}
+	cName, _ := getName(decl)
+	goName := gen.tr.TransformName(tl.TargetType, cName)
+	for _, helper := range gen.getCastStructHelpers(goName, decl.Spec) {
+		gen.submitHelper(helper)
+	}
+	gen.submitHelper(gen.getCLayoutHelper(goName, decl.Spec.(*tl.CStructSpec)))
+	gen.mirrorStructs = append(gen.mirrorStructs, decl)
+}
+
+func (gen *Generator) getCLayoutHelper(goName []byte, spec *tl.CStructSpec) *Helper {
+	name := fmt.Sprintf("cLayoutOf%s", goName)
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "func %s() []uintptr {\n", name)
+	fmt.Fprintln(buf, "return []uintptr{")
+	fmt.Fprintf(buf, "C.sizeof_%s,\n", spec.CGoName())
+	for _, m := range gen.layoutMembers(spec) {
+		fmt.Fprintf(buf, "unsafe.Offsetof(C.%s{}.%s),\n", spec.CGoName(), m.Name)
+	}
+	fmt.Fprintln(buf, "}")
+	buf.WriteRune('}')
+	return &Helper{
+		Name:        name,
+		Description: fmt.Sprintf("%s returns the size of C %s and the offsets of its members.", name, spec.CGoName()),
+		Source:      buf.String(),
+	}
+}
+
+// WriteLayoutTests writes the tests that check the layout of the mirror structs against
+// the layout of the C structs seen by cgo, since cgo is not available in the tests.
+func (gen *Generator) WriteLayoutTests(wr io.Writer) int {
+	const public = true
+	for _, decl := range gen.mirrorStructs {
+		cName, _ := getName(decl)
+		goName := gen.tr.TransformName(tl.TargetType, cName)
+		names := []string{strconv.Quote("size")}
+		values := []string{fmt.Sprintf("unsafe.Sizeof(%s{})", goName)}
+		for _, m := range gen.layoutMembers(decl.Spec.(*tl.CStructSpec)) {
+			names = append(names, strconv.Quote(m.Name))
+			values = append(values, fmt.Sprintf("unsafe.Offsetof(%s{}.%s)", goName,
+				gen.tr.TransformName(tl.TargetType, m.Name, public)))
+		}
+		fmt.Fprintf(wr, "func Test%sLayout(t *testing.T) {\n", goName)
+		fmt.Fprintf(wr, "names := []string{%s}\n", strings.Join(names, ", "))
+		fmt.Fprintf(wr, "want := cLayoutOf%s()\n", goName)
+		fmt.Fprintf(wr, "got := []uintptr{\n%s,\n}\n", strings.Join(values, ",\n"))
+		fmt.Fprintf(wr, `for i, name := range names {
+			if got[i] != want[i] {
+				t.Errorf("%s: %%s is %%d in Go, %%d in C", name, got[i], want[i])
+			}
+		}`, goName)
+		writeEndFuncBody(wr)
+		writeSpace(wr, 1)
+	}
+	return len(gen.mirrorStructs)
+}
@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	tl "github.com/xlab/c-for-go/translator"
+)
+
+// WriteMacros writes Go functions for the function-like macros. With the expand const rule
+// the body is the translated macro expression, with the cgo rule it calls a C wrapper
+// of the macro, the wrappers are submitted as helpers.
+func (gen *Generator) WriteMacros(wr io.Writer) int {
+	var count int
+	cgo := gen.tr.ConstRules()[tl.ConstMacros] == tl.ConstCGOAlias
+	for _, decl := range gen.tr.Macros() {
+		if cgo && gen.noCGO {
+			gen.tr.Diagnostics().Add(tl.DiagSkipped, decl.Pos, decl.Name, "the C wrappers of macros require cgo")
+			continue
+		}
+		if cgo {
+			for _, helper := range gen.getMacroWrapperHelpers(decl) {
+				gen.submitHelper(helper)
+			}
+		}
+		gen.writeMacroFunc(wr, decl, cgo)
+		writeSpace(wr, 1)
+		count++
+	}
+	return count
+}
+
+func (gen *Generator) writeMacroFunc(wr io.Writer, decl *tl.CDecl, cgo bool) {
+	spec := decl.Spec.(*tl.CFunctionSpec)
+	goName := gen.tr.TransformName(tl.TargetFunction, decl.Name)
+	goType := gen.tr.TranslateSpec(spec.Return)
+
+	gen.writeDocComment(wr, decl.Doc, true)
+	fmt.Fprintf(wr, "// %s function-like macro as defined in %s\n", goName,
+		filepath.ToSlash(gen.tr.SrcLocation(tl.TargetFunction, decl.Name, decl.Pos)))
+	paramNames := make([]string, 0, len(spec.Params))
+	for _, param := range spec.Params {
+		paramNames = append(paramNames, param.Name)
+	}
+	if len(paramNames) > 0 {
+		fmt.Fprintf(wr, "func %s(%s %s) %s", goName, strings.Join(paramNames, ", "), goType, goType)
+	} else {
+		fmt.Fprintf(wr, "func %s() %s", goName, goType)
+	}
+	writeStartFuncBody(wr)
+	if !cgo {
+		fmt.Fprintf(wr, "return %s(%s)\n", goType, decl.Expression)
+		writeEndFuncBody(wr)
+		return
+	}
+	cgoType := gen.tr.CGoSpec(spec.Return, true)
+	args := make([]string, 0, len(spec.Params))
+	for _, name := range paramNames {
+		args = append(args, fmt.Sprintf("%s(%s)", cgoType, name))
+	}
+	fmt.Fprintf(wr, "return %s(C.%s(%s))\n", goType, macroWrapperName(decl.Name), strings.Join(args, ", "))
+	writeEndFuncBody(wr)
+}
+
+func macroWrapperName(name string) string {
+	return "cgo_macro_" + name
+}
+
+func (gen *Generator) getMacroWrapperHelpers(decl *tl.CDecl) (helpers []*Helper) {
+	spec := decl.Spec.(*tl.CFunctionSpec)
+	wrapperName := macroWrapperName(decl.Name)
+	typeSpec := gen.tr.NormalizeSpecPointers(spec.Return).String()
+	var params []string
+	var paramNames []string
+	for _, param := range spec.Params {
+		params = append(params, fmt.Sprintf("%s %s", typeSpec, param.Name))
+		paramNames = append(paramNames, param.Name)
+	}
+	paramList := "void"
+	if len(params) > 0 {
+		paramList = strings.Join(params, ", ")
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "%s %s(%s);", typeSpec, wrapperName, paramList)
+	helpers = append(helpers, &Helper{
+		Name:        wrapperName,
+		Description: fmt.Sprintf("%s calls function-like macro %s.", wrapperName, decl.Name),
+		Source:      buf.String(),
+		Side:        CHSide,
+	})
+
+	buf = new(bytes.Buffer)
+	fmt.Fprintf(buf, "%s %s(%s) {\n", typeSpec, wrapperName, paramList)
+	fmt.Fprintf(buf, "\treturn %s(%s);\n", decl.Name, strings.Join(paramNames, ", "))
+	buf.WriteRune('}')
+	helpers = append(helpers, &Helper{
+		Name:   wrapperName,
+		Source: buf.String(),
+		Side:   CCSide,
+	})
+	return
+}
@@ -0,0 +1,510 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	tl "github.com/xlab/c-for-go/translator"
+)
+
+// DisableCGO switches the generator to the pure Go output: the structs are laid out
+// by the data model of the target, the functions are called through a Loader and
+// a Trampoline set by the package user, so the bindings build with CGO_ENABLED=0.
+func (gen *Generator) DisableCGO() {
+	gen.noCGO = true
+}
+
+// writeLayoutStruct writes a Go struct that has the same layout as the C struct,
+// the padding between the members is explicit. The members that cannot be aligned
+// the same way in Go, e.g. the members of packed structs, are written as byte arrays.
+// It reports whether the struct has been written.
+func (gen *Generator) writeLayoutStruct(wr io.Writer, decl *tl.CDecl, seenNames map[string]bool) bool {
+	cName, ok := getName(decl)
+	if !ok {
+		return false
+	}
+	goName := gen.tr.TransformName(tl.TargetType, cName)
+	if seenNames[string(goName)] {
+		return false
+	}
+	seenNames[string(goName)] = true
+
+	spec := decl.Spec.(*tl.CStructSpec)
+	gen.writeDocComment(wr, decl.Doc, true)
+	fmt.Fprintf(wr, "// %s as declared in %s\n", goName,
+		filepath.ToSlash(gen.tr.SrcLocation(tl.TargetType, cName, decl.Pos)))
+	if !spec.IsComplete() {
+		fmt.Fprintf(wr, "type %s struct{}", goName)
+		writeSpace(wr, 1)
+		return true
+	}
+	fmt.Fprintf(wr, "type %s struct {", goName)
+	writeSpace(wr, 1)
+
+	const public = true
+	var offset int
+	var pads int
+	writePadding := func(n int) {
+		fmt.Fprintf(wr, "_%d [%d]byte\n", pads, n)
+		pads++
+		offset += n
+	}
+	storages := bitStoragesOf(spec)
+	if len(storages) > 0 {
+		gen.writeBitFieldAlign(wr, spec)
+	}
+	for _, m := range spec.Members {
+		if m.Size <= 0 && !m.IsBitField() {
+			continue
+		}
+		var name, goType string
+		memberOffset, size, align := m.Offset, m.Size, m.Align
+		if m.IsBitField() {
+			st, ok := storages[m]
+			if !ok {
+				continue
+			}
+			name = st.name()
+			memberOffset, size, align = st.offset, st.size, 1
+		} else {
+			name = string(checkName(gen.tr.TransformName(tl.TargetType, m.Name, public)))
+			goType = gen.layoutTypeOf(m.Spec)
+		}
+		if memberOffset > offset {
+			writePadding(memberOffset - offset)
+		}
+		if len(goType) == 0 || memberOffset < offset || offset%gen.goAlignOf(align) != 0 {
+			goType = fmt.Sprintf("[%d]byte", size)
+		}
+		if !m.IsBitField() {
+			gen.writeDocComment(wr, m.Doc, false)
+		}
+		fmt.Fprintf(wr, "%s %s\n", name, goType)
+		offset = memberOffset + size
+	}
+	if spec.Size > offset {
+		writePadding(spec.Size - offset)
+	}
+	writeEndStruct(wr)
+	writeSpace(wr, 1)
+	gen.writeLayoutConsts(wr, goName, spec)
+
+	for i, m := range spec.Members {
+		if !m.IsBitField() || len(m.Name) == 0 {
+			continue
+		}
+		ptrTipRx, typeTipRx, _ := gen.tr.TipRxsForSpec(tl.TipScopeType, cName, spec)
+		goSpec := gen.tr.TranslateSpec(m.Spec, ptrTipRx.TipAt(i), typeTipRx.TipAt(i))
+		getterName := string(gen.tr.TransformName(tl.TargetType, m.Name, public))
+		for _, helper := range gen.getBitFieldHelpers(goName, getterName, m, goSpec, bitUnitRef(m)) {
+			gen.submitHelper(helper)
+		}
+	}
+	return true
+}
+
+// writeLayoutUnion writes a Go array type that has the size and the alignment of the C union.
+func (gen *Generator) writeLayoutUnion(wr io.Writer, decl *tl.CDecl) {
+	cName, ok := getName(decl)
+	if !ok {
+		return
+	}
+	goName := gen.tr.TransformName(tl.TargetType, cName)
+	spec := decl.Spec.(*tl.CStructSpec)
+	gen.writeDocComment(wr, decl.Doc, true)
+	fmt.Fprintf(wr, "// %s as declared in %s\n", goName,
+		filepath.ToSlash(gen.tr.SrcLocation(tl.TargetType, cName, decl.Pos)))
+	fmt.Fprintf(wr, "type %s %s\n", goName, gen.opaqueLayoutOf(spec.Size, spec.Align))
+	writeSpace(wr, 1)
+	gen.writeLayoutConsts(wr, goName, spec)
+}
+
+// writeFunctionPtrTypedef writes the type of C function pointers, those
+// are the addresses of C functions, e.g. the ones resolved by the Loader.
+func (gen *Generator) writeFunctionPtrTypedef(wr io.Writer, decl *tl.CDecl, seenNames map[string]bool) {
+	goName := gen.tr.TransformName(tl.TargetType, decl.Name)
+	if seenNames[string(goName)] {
+		return
+	}
+	seenNames[string(goName)] = true
+	gen.writeDocComment(wr, decl.Doc, true)
+	fmt.Fprintf(wr, "// %s type as declared in %s\n", goName,
+		filepath.ToSlash(gen.tr.SrcLocation(tl.TargetFunction, decl.Name, decl.Pos)))
+	fmt.Fprintf(wr, "type %s uintptr", goName)
+	writeSpace(wr, 1)
+}
+
+// opaqueLayoutOf returns an array of words that has the given size and alignment.
+func (gen *Generator) opaqueLayoutOf(size, align int) string {
+	align = gen.goAlignOf(align)
+	if align <= 1 || size%align != 0 {
+		return fmt.Sprintf("[%d]byte", size)
+	}
+	return fmt.Sprintf("[%d]uint%d", size/align, align*8)
+}
+
+// goAlignOf returns the alignment Go uses for a value with the C alignment,
+// the 64-bit values are aligned by 4 bytes on the 32-bit arches.
+func (gen *Generator) goAlignOf(align int) int {
+	if align < 1 {
+		return 1
+	}
+	if maxAlign := gen.tr.PointerSize(); align > maxAlign {
+		return maxAlign
+	}
+	return align
+}
+
+// layoutTypeOf returns the Go type of a struct member that has the layout of the C type,
+// the pointers are unsafe.Pointer since those may point to C memory. It returns nothing
+// if there is no such type, e.g. for the structs that are converted by copying with cgo.
+func (gen *Generator) layoutTypeOf(spec tl.CType) string {
+	arrays := spec.OuterArrays().String()
+	switch spec.Kind() {
+	case tl.FunctionKind:
+		if !gen.noCGO {
+			// the function typedefs are Go funcs with cgo
+			return arrays + "unsafe.Pointer"
+		}
+		if typedef := spec.(*tl.CFunctionSpec).Typedef; len(typedef) > 0 &&
+			gen.tr.IsAcceptableName(tl.TargetType, typedef) {
+			return arrays + string(gen.tr.TransformName(tl.TargetType, typedef))
+		}
+		return arrays + "uintptr"
+	}
+	if spec.GetPointers() > 0 {
+		return arrays + "unsafe.Pointer"
+	}
+	switch spec.Kind() {
+	case tl.StructKind, tl.UnionKind:
+		base := spec.GetBase()
+		if !gen.tr.IsAcceptableName(tl.TargetType, base) {
+			return ""
+		}
+		if spec.Kind() == tl.StructKind && !gen.noCGO && !gen.MemTipOf(&tl.CDecl{Spec: spec}).HasCLayout() {
+			return ""
+		}
+		return arrays + string(gen.tr.TransformName(tl.TargetType, base))
+	case tl.OpaqueStructKind:
+		return ""
+	}
+	return gen.tr.TranslateSpec(spec).String()
+}
+
+// dynamicValue describes how a value is passed to a C function called
+// through the Trampoline and how it's returned.
+type dynamicValue struct {
+	goType string
+	// local converts the Go value to a value that must be kept alive
+	// until the C call returns, e.g. a copy of the string.
+	local func(name string) string
+	// arg converts the Go value, or its local conversion, to the machine word.
+	arg func(name string) string
+	// ret converts the machine word to the Go value.
+	ret func(word string) string
+	// keepAlive is set for the pointers to Go memory passed to C.
+	keepAlive  bool
+	argHelpers []*Helper
+	retHelpers []*Helper
+}
+
+func (gen *Generator) dynamicValueOf(spec tl.CType, isArg bool) (dynamicValue, error) {
+	word := func(name string) string {
+		return fmt.Sprintf("uintptr(%s)", name)
+	}
+	if spec.Kind() == tl.FunctionKind {
+		goType := gen.layoutTypeOf(spec)
+		return dynamicValue{
+			goType: goType,
+			arg:    word,
+			ret: func(word string) string {
+				return fmt.Sprintf("%s(%s)", goType, word)
+			},
+		}, nil
+	}
+	if spec.GetPointers() > 0 || len(spec.OuterArrays()) > 0 || spec.GetBase() == "void*" {
+		pointers := int(spec.GetPointers()) + len(spec.OuterArrays())
+		base := spec.GetBase()
+		switch {
+		case pointers == 1 && spec.Kind() == tl.TypeKind && base == "char" && (spec.IsConst() || !isArg):
+			return dynamicValue{
+				goType: "string",
+				local: func(name string) string {
+					return fmt.Sprintf("cString(%s)", name)
+				},
+				arg: func(name string) string {
+					return fmt.Sprintf("uintptr(unsafe.Pointer(%s))", name)
+				},
+				ret: func(word string) string {
+					return fmt.Sprintf("goString(ptrOf(%s))", word)
+				},
+				keepAlive:  true,
+				argHelpers: []*Helper{cStringHelper},
+				retHelpers: []*Helper{goStringHelper, ptrOfHelper},
+			}, nil
+		case pointers == 1 && spec.Kind() == tl.StructKind && gen.tr.IsAcceptableName(tl.TargetType, base):
+			goType := "*" + string(gen.tr.TransformName(tl.TargetType, base))
+			return dynamicValue{
+				goType: goType,
+				arg: func(name string) string {
+					return fmt.Sprintf("uintptr(unsafe.Pointer(%s))", name)
+				},
+				ret: func(word string) string {
+					return fmt.Sprintf("(%s)(ptrOf(%s))", goType, word)
+				},
+				keepAlive:  true,
+				retHelpers: []*Helper{ptrOfHelper},
+			}, nil
+		}
+		return dynamicValue{
+			goType: "unsafe.Pointer",
+			arg:    word,
+			ret: func(word string) string {
+				return fmt.Sprintf("ptrOf(%s)", word)
+			},
+			keepAlive:  true,
+			retHelpers: []*Helper{ptrOfHelper},
+		}, nil
+	}
+	switch spec.Kind() {
+	case tl.StructKind, tl.OpaqueStructKind, tl.UnionKind:
+		return dynamicValue{}, errors.New("structs and unions are not passed by value through the trampoline")
+	}
+	goSpec := gen.tr.TranslateSpec(spec)
+	goType := goSpec.String()
+	switch {
+	case goSpec.Base == "float":
+		return dynamicValue{}, errors.New("floating-point values are not passed by the trampoline")
+	case goSpec.Bits == 64 && gen.tr.PointerSize() < 8:
+		return dynamicValue{}, errors.New("64-bit values do not fit the machine words of the target")
+	case goSpec.Base == "bool":
+		return dynamicValue{
+			goType: goType,
+			arg: func(name string) string {
+				return fmt.Sprintf("boolWord(bool(%s))", name)
+			},
+			ret: func(word string) string {
+				return fmt.Sprintf("%s(%s&0xff != 0)", goType, word)
+			},
+			argHelpers: []*Helper{boolWordHelper},
+		}, nil
+	}
+	return dynamicValue{
+		goType: goType,
+		arg:    word,
+		ret: func(word string) string {
+			return fmt.Sprintf("%s(%s)", goType, word)
+		},
+	}, nil
+}
+
+// WriteDynamicDeclares writes the Go functions that call the C functions through
+// the Trampoline, the symbols are resolved by the Loader on the first call.
+func (gen *Generator) WriteDynamicDeclares(wr io.Writer) int {
+	var count int
+	seenFunctions := make(map[string]bool)
+	for _, decl := range gen.tr.Declares() {
+		if decl.Spec.Kind() != tl.FunctionKind {
+			continue
+		} else if !gen.tr.IsAcceptableName(tl.TargetFunction, decl.Name) {
+			gen.reportFiltered(decl, decl.Name)
+			continue
+		} else if seenFunctions[decl.Name] {
+			continue
+		}
+		seenFunctions[decl.Name] = true
+		if err := gen.writeDynamicFunction(wr, decl); err != nil {
+			gen.tr.Diagnostics().Add(tl.DiagSkipped, decl.Pos, decl.Name, err.Error()+" without cgo")
+			continue
+		}
+		writeSpace(wr, 1)
+		count++
+	}
+	return count
+}
+
+func (gen *Generator) writeDynamicFunction(wr io.Writer, decl *tl.CDecl) error {
+	spec := decl.Spec.(*tl.CFunctionSpec)
+	if spec.IsVariadic {
+		return errors.New("variadic functions are not supported")
+	}
+	const public = false
+	var helpers []*Helper
+	var params, locals, args, keepAlive []string
+	for _, param := range spec.Params {
+		value, err := gen.dynamicValueOf(param.Spec, true)
+		if err != nil {
+			return err
+		}
+		name := string(checkName(gen.tr.TransformName(tl.TargetType, param.Name, public)))
+		params = append(params, fmt.Sprintf("%s %s", name, value.goType))
+		if value.local != nil {
+			local := "c" + name
+			locals = append(locals, fmt.Sprintf("%s := %s", local, value.local(name)))
+			name = local
+		}
+		args = append(args, value.arg(name))
+		if value.keepAlive {
+			keepAlive = append(keepAlive, name)
+		}
+		helpers = append(helpers, value.argHelpers...)
+	}
+	var ret dynamicValue
+	if spec.Return != nil {
+		var err error
+		if ret, err = gen.dynamicValueOf(spec.Return, false); err != nil {
+			return err
+		}
+		helpers = append(helpers, ret.retHelpers...)
+	}
+
+	goName := gen.tr.TransformName(tl.TargetFunction, decl.Name, true)
+	symName := "sym" + string(goName)
+	fmt.Fprintf(wr, "var %s = &librarySymbol{name: %q}\n\n", symName, decl.Name)
+	gen.writeDocComment(wr, decl.Doc, true)
+	fmt.Fprintf(wr, "// %s function as declared in %s\n", goName,
+		filepath.ToSlash(gen.tr.SrcLocation(tl.TargetFunction, decl.Name, decl.Pos)))
+	fmt.Fprintf(wr, "func %s(%s)", goName, strings.Join(params, ", "))
+	if spec.Return != nil {
+		fmt.Fprintf(wr, " %s", ret.goType)
+	}
+	writeStartFuncBody(wr)
+	for _, local := range locals {
+		fmt.Fprintln(wr, local)
+	}
+	call := fmt.Sprintf("%s.call(%s)", symName, strings.Join(args, ", "))
+	switch {
+	case spec.Return == nil:
+		fmt.Fprintln(wr, call)
+	case len(keepAlive) > 0:
+		fmt.Fprintf(wr, "__ret := %s\n", call)
+	default:
+		fmt.Fprintf(wr, "return %s\n", ret.ret(call))
+	}
+	for _, name := range keepAlive {
+		fmt.Fprintf(wr, "runtime.KeepAlive(%s)\n", name)
+	}
+	if spec.Return != nil && len(keepAlive) > 0 {
+		fmt.Fprintf(wr, "return %s\n", ret.ret("__ret"))
+	}
+	writeEndFuncBody(wr)
+
+	gen.submitHelper(gen.getLibraryHelper())
+	for _, helper := range helpers {
+		gen.submitHelper(helper)
+	}
+	return nil
+}
+
+func (gen *Generator) getLibraryHelper() *Helper {
+	return &Helper{
+		Name: "Loader",
+		Description: "Loader opens a shared library and looks up its symbols, it's implemented\n" +
+			"on top of dlopen and dlsym, or LoadLibrary and GetProcAddress on Windows.",
+		Source: fmt.Sprintf(`type Loader interface {
+			Open(path string) (handle uintptr, err error)
+			Lookup(handle uintptr, name string) (addr uintptr, err error)
+		}
+
+		// Trampoline calls the C function at addr passing the args in the machine words,
+		// it has the signature of purego.SyscallN and syscall.SyscallN on Windows.
+		type Trampoline func(addr uintptr, args ...uintptr) (r1, r2, err uintptr)
+
+		var library struct {
+			mux    sync.RWMutex
+			handle uintptr
+			loader Loader
+			call   Trampoline
+		}
+
+		// Load opens the shared library at path with the loader, the functions
+		// of the package look up their symbols in it and call them with call.
+		func Load(loader Loader, call Trampoline, path string) error {
+			library.mux.Lock()
+			defer library.mux.Unlock()
+			if library.loader != nil {
+				return errors.New("%[1]s: the library is already loaded")
+			}
+			handle, err := loader.Open(path)
+			if err != nil {
+				return err
+			}
+			library.handle = handle
+			library.loader = loader
+			library.call = call
+			return nil
+		}
+
+		// librarySymbol is a C function looked up in the library on the first call.
+		type librarySymbol struct {
+			name string
+			addr uintptr
+		}
+
+		func (s *librarySymbol) call(args ...uintptr) uintptr {
+			library.mux.RLock()
+			handle, loader, call := library.handle, library.loader, library.call
+			library.mux.RUnlock()
+			if loader == nil {
+				panic("%[1]s: the library is not loaded, call Load first")
+			}
+			addr := atomic.LoadUintptr(&s.addr)
+			if addr == 0 {
+				var err error
+				if addr, err = loader.Lookup(handle, s.name); err != nil {
+					panic(fmt.Sprintf("%[1]s: %%s: %%v", s.name, err))
+				}
+				atomic.StoreUintptr(&s.addr, addr)
+			}
+			r1, _, _ := call(addr, args...)
+			return r1
+		}`, gen.pkg),
+	}
+}
+
+var cStringHelper = &Helper{
+	Name:        "cString",
+	Description: "cString returns a NUL-terminated copy of s, it must be kept alive until the C call returns.",
+	Source: `func cString(s string) *byte {
+		b := make([]byte, len(s)+1)
+		copy(b, s)
+		return &b[0]
+	}`,
+}
+
+var goStringHelper = &Helper{
+	Name:        "goString",
+	Description: "goString copies the NUL-terminated C string at p.",
+	Source: `func goString(p unsafe.Pointer) string {
+		if p == nil {
+			return ""
+		}
+		var n int
+		for *(*byte)(unsafe.Pointer(uintptr(p) + uintptr(n))) != 0 {
+			n++
+		}
+		return string((*[1 << 30]byte)(p)[:n:n])
+	}`,
+}
+
+var ptrOfHelper = &Helper{
+	Name:        "ptrOf",
+	Description: "ptrOf converts the address returned by a C function to a pointer.",
+	Source: `func ptrOf(addr uintptr) unsafe.Pointer {
+		return *(*unsafe.Pointer)(unsafe.Pointer(&addr))
+	}`,
+}
+
+var boolWordHelper = &Helper{
+	Name:        "boolWord",
+	Description: "boolWord converts v to the machine word passed to C.",
+	Source: `func boolWord(v bool) uintptr {
+		if v {
+			return 1
+		}
+		return 0
+	}`,
+}
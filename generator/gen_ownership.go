@@ -0,0 +1,204 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+
+	tl "github.com/xlab/c-for-go/translator"
+)
+
+// ownTipOf returns the ownership tip of the parameter at i, or of the return value if i < 0,
+// the ownership tips are listed along with either the ptr or the mem tips of the function.
+func ownTipOf(ptrTipRx, memTipRx tl.TipSpecRx, i int) tl.Tip {
+	for _, rx := range []tl.TipSpecRx{ptrTipRx, memTipRx} {
+		tip := rx.OwnSelf()
+		if i >= 0 {
+			tip = rx.OwnTipAt(i)
+		}
+		if tip.IsValid() {
+			return tip
+		}
+	}
+	return tl.NoTip
+}
+
+// releaseCall returns the C call that releases the memory at ptr, an unsafe.Pointer expression,
+// with the function named by the free-with tip, or with C free for the other tips.
+func (gen *Generator) releaseCall(decl *tl.CDecl, tip tl.Tip, ptr string) (string, bool) {
+	fn := tip.FreeWith()
+	if len(fn) == 0 {
+		return fmt.Sprintf("C.free(%s)", ptr), true
+	}
+	for _, d := range gen.tr.Declares() {
+		if d.Name != fn || d.Spec.Kind() != tl.FunctionKind {
+			continue
+		}
+		params := d.Spec.(*tl.CFunctionSpec).Params
+		if len(params) != 1 || params[0].Spec.GetPointers() == 0 {
+			break
+		}
+		cgoSpec := gen.tr.CGoSpec(params[0].Spec, true)
+		return fmt.Sprintf("C.%s((%s)(%s))", fn, cgoSpec, ptr), true
+	}
+	gen.tr.Diagnostics().Add(tl.DiagDegraded, decl.Pos, decl.Name,
+		fmt.Sprintf("%s is not declared as a function taking a single pointer, the %s tip is ignored", fn, tip))
+	return "", false
+}
+
+// ownedResult describes how the result of a function owned by Go is released.
+type ownedResult struct {
+	// goType is the type of the Go wrapper that releases the memory once unreachable,
+	// it's empty for the strings that are copied and released at once.
+	goType  string
+	release string
+}
+
+var goTypeNameRx = regexp.MustCompile(`^\*([A-Z][A-Za-z0-9_]*)$`)
+
+// ownedTypes returns the names of the Go types wrapped by OwnedX, those are the pointers returned
+// with the own, transfer or free-with tip, along with the C functions named by free-with by the wrapper types.
+func (gen *Generator) ownedTypes() (types map[string]bool, releasedBy map[string]string) {
+	if gen.owned != nil {
+		return gen.owned, gen.releasedBy
+	}
+	gen.owned = make(map[string]bool)
+	gen.releasedBy = make(map[string]string)
+	for _, decl := range gen.tr.Declares() {
+		if decl.Spec.Kind() != tl.FunctionKind || !gen.tr.IsAcceptableName(tl.TargetFunction, decl.Name) {
+			continue
+		}
+		spec := decl.Spec.(*tl.CFunctionSpec)
+		ptrTipRx, _, memTipRx := gen.tr.TipRxsForSpec(tl.TipScopeFunction, decl.Name, decl.Spec)
+		tip := ownTipOf(ptrTipRx, memTipRx, -1)
+		if spec.Return == nil || !tip.IsValid() || tip == tl.TipBorrow {
+			continue
+		}
+		ptrTip, typeTip := gen.functionTips(decl.Name)
+		goSpec := gen.tr.TranslateSpec(spec.Return, ptrTip, typeTip)
+		if m := goTypeNameRx.FindStringSubmatch(goSpec.String()); m != nil {
+			gen.owned[m[1]] = true
+			if fn := tip.FreeWith(); len(fn) > 0 {
+				gen.releasedBy[fn] = "Owned" + m[1]
+			}
+		}
+	}
+	return gen.owned, gen.releasedBy
+}
+
+// ownedTypeOf returns the name of the wrapper type of the Go type if its values are owned by Go,
+// the functions take the wrappers in place of the pointers, so the memory stays alive during the calls.
+func (gen *Generator) ownedTypeOf(goSpec tl.GoTypeSpec) (string, bool) {
+	m := goTypeNameRx.FindStringSubmatch(goSpec.String())
+	if m == nil {
+		return "", false
+	}
+	if types, _ := gen.ownedTypes(); !types[m[1]] {
+		return "", false
+	}
+	return "Owned" + m[1], true
+}
+
+// ownedParams returns the names of the params of the function taken as the wrappers of owned values.
+func (gen *Generator) ownedParams(decl *tl.CDecl) []string {
+	var names []string
+	spec := decl.Spec.(*tl.CFunctionSpec)
+	ptrTipRx, typeTipRx, _ := gen.tr.TipRxsForSpec(tl.TipScopeFunction, decl.Name, decl.Spec)
+	for i, param := range spec.Params {
+		goSpec := gen.tr.TranslateSpec(param.Spec, ptrTipRx.TipAt(i), typeTipRx.TipAt(i))
+		if _, ok := gen.ownedTypeOf(goSpec); ok {
+			const public = false
+			names = append(names, string(gen.tr.TransformName(tl.TargetType, param.Name, public)))
+		}
+	}
+	return names
+}
+
+// isReleaseFunc reports whether the C function is named by a free-with tip, such a function
+// is called by the Free method of the wrapper type, so its binding is not written.
+func (gen *Generator) isReleaseFunc(decl *tl.CDecl) bool {
+	_, releasedBy := gen.ownedTypes()
+	typeName, ok := releasedBy[decl.Name]
+	if ok {
+		gen.tr.Diagnostics().Add(tl.DiagSkipped, decl.Pos, decl.Name,
+			fmt.Sprintf("the function is called by the Free method of %s", typeName))
+	}
+	return ok
+}
+
+// ownedResultOf returns the way the result of the function is released, if the function
+// has the own, transfer or free-with tip for the return value, or nil otherwise.
+func (gen *Generator) ownedResultOf(decl *tl.CDecl, goSpec tl.GoTypeSpec) *ownedResult {
+	ptrTipRx, _, memTipRx := gen.tr.TipRxsForSpec(tl.TipScopeFunction, decl.Name, decl.Spec)
+	tip := ownTipOf(ptrTipRx, memTipRx, -1)
+	if !tip.IsValid() || tip == tl.TipBorrow {
+		return nil
+	}
+	if goSpec.IsGoString() && goSpec.Pointers == 0 {
+		release, ok := gen.releaseCall(decl, tip, "unsafe.Pointer(__ret)")
+		if !ok {
+			return nil
+		}
+		return &ownedResult{release: release}
+	}
+	m := goTypeNameRx.FindStringSubmatch(goSpec.String())
+	if m == nil {
+		gen.tr.Diagnostics().Add(tl.DiagDegraded, decl.Pos, decl.Name,
+			fmt.Sprintf("the %s tip is not applicable to the %s result", tip, goSpec))
+		return nil
+	}
+	release, ok := gen.releaseCall(decl, tip, "unsafe.Pointer(__ret)")
+	if !ok {
+		return nil
+	}
+	helper := getOwnedHelper(m[1])
+	gen.submitHelper(helper)
+	return &ownedResult{goType: "*" + helper.Name, release: release}
+}
+
+// isBorrowedString reports whether the function returns a string with the borrow tip, such a string
+// points into the memory owned by C, so it's copied rather than viewed as it may be released later.
+func (gen *Generator) isBorrowedString(decl *tl.CDecl, goSpec tl.GoTypeSpec) bool {
+	ptrTipRx, _, memTipRx := gen.tr.TipRxsForSpec(tl.TipScopeFunction, decl.Name, decl.Spec)
+	return ownTipOf(ptrTipRx, memTipRx, -1) == tl.TipBorrow && goSpec.IsGoString() && goSpec.Pointers == 0
+}
+
+func getOwnedHelper(typeName string) *Helper {
+	name := "Owned" + typeName
+	return &Helper{
+		Name: name,
+		Description: fmt.Sprintf("%s holds a %s which C memory is owned by Go, the memory is released\n"+
+			"once the %s becomes unreachable, or by Free. The functions take the %s\n"+
+			"and keep it alive until they return.", name, typeName, name, name),
+		Source: fmt.Sprintf(`type %[1]s struct {
+			ref     *%[2]s
+			release func()
+		}
+
+		func new%[1]s(ref *%[2]s, release func()) *%[1]s {
+			if ref == nil {
+				return nil
+			}
+			owned := &%[1]s{ref: ref, release: release}
+			runtime.SetFinalizer(owned, (*%[1]s).Free)
+			return owned
+		}
+
+		// Free releases the C memory right away, the %[1]s must not be used after that.
+		func (o *%[1]s) Free() {
+			if o == nil || o.ref == nil {
+				return
+			}
+			runtime.SetFinalizer(o, nil)
+			o.release()
+			o.ref = nil
+		}
+
+		// pointer returns the held %[2]s, it is nil once the memory has been released.
+		func (o *%[1]s) pointer() *%[2]s {
+			if o == nil {
+				return nil
+			}
+			return o.ref
+		}`, name, typeName),
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"hash/crc32"
+	"io"
 
 	tl "github.com/xlab/c-for-go/translator"
 )
@@ -37,7 +38,7 @@ func (gen *Generator) getStructMembersHelpers(cStructName string, spec tl.CType)
 			memTip = gen.MemTipOf(m)
 		}
 		ptrTip := ptrTipRx.TipAt(i)
-		if memTip == tl.TipMemRaw {
+		if memTip.HasCLayout() {
 			ptrTip = tl.TipPtrSRef
 		}
 		typeTip := typeTipRx.TipAt(i)
@@ -207,25 +208,56 @@ func (gen *Generator) getStructHelpers(goStructName []byte, cStructName string,
 
 	buf.Reset()
 	fmt.Fprintf(buf, "func (x *%s) GC(a *cgoAllocMap, args ...*cgoAllocMap)", goStructName)
-	fmt.Fprintf(buf, `{
-		for i := range args {
-			a.Borrow(args[i])
-		}
-		if len(a.m) > 0 {
-			for ptr := range a.m {
-				fmt.Printf("INFO: MEMORY: [PTR %%p] GC register\n", ptr)
+	if gen.cfg.Options.Finalizers {
+		// the allocations join the ones tracked since passRef, only one finalizer can be set
+		fmt.Fprintf(buf, `{
+			for i := range args {
+				a.Borrow(args[i])
 			}
-			runtime.SetFinalizer(x, func(*%s) {
-				a.Free()
-			})
-		}
-	}`, goStructName)
+			trackAllocs(x, %q, a)
+		}`, goStructName)
+	} else {
+		fmt.Fprintf(buf, `{
+			for i := range args {
+				a.Borrow(args[i])
+			}
+			if len(a.m) > 0 {
+				runtime.SetFinalizer(x, func(*%s) {
+					a.Free()
+				})
+			}
+		}`, goStructName)
+	}
 	helpers = append(helpers, &Helper{
 		Name:        fmt.Sprintf("%s.GC", goStructName),
 		Description: "GC is register for garbage collection.",
 		Source:      buf.String(),
 	})
 
+	if gen.cfg.Options.Finalizers {
+		buf.Reset()
+		fmt.Fprintf(buf, `func (x *%s) Free() {
+			untrackAllocs(x)
+		}`, goStructName)
+		helpers = append(helpers, &Helper{
+			Name:        fmt.Sprintf("%s.Free", goStructName),
+			Description: "Free releases the C memory allocated for the members right away and disarms the finalizer.",
+			Source:      buf.String(),
+			Requires:    Helpers{gen.getTrackAllocsHelper()},
+		})
+	}
+
+	ptrTipRx, typeTipRx, memTipRx := gen.tr.TipRxsForSpec(tl.TipScopeType, cStructName, spec)
+	for i, m := range spec.(*tl.CStructSpec).Members {
+		if !m.IsBitField() || len(m.Name) == 0 {
+			continue
+		}
+		goSpec := gen.tr.TranslateSpec(m.Spec, ptrTipRx.TipAt(i), typeTipRx.TipAt(i))
+		const public = true
+		goName := string(gen.tr.TransformName(tl.TargetType, m.Name, public))
+		helpers = append(helpers, gen.getBitFieldHelpers(goStructName, goName, m, goSpec, bitUnitRef(m))...)
+	}
+
 	// buf.Reset()
 	// fmt.Fprintf(buf, "func (x *%s) Deref() {\n", goStructName)
 	// buf.Write(gen.getDerefSource(goStructName, cStructName, spec))
@@ -247,7 +279,6 @@ func (gen *Generator) getStructHelpers(goStructName []byte, cStructName string,
 	if !gen.cfg.Options.StructAccessors {
 		return
 	}
-	ptrTipRx, typeTipRx, memTipRx := gen.tr.TipRxsForSpec(tl.TipScopeType, cStructName, spec)
 	for i, m := range structSpec.Members {
 		if len(m.Name) == 0 {
 			continue
@@ -265,7 +296,7 @@ func (gen *Generator) getStructHelpers(goStructName []byte, cStructName string,
 			memTip = gen.MemTipOf(m)
 		}
 		ptrTip := ptrTipRx.TipAt(i)
-		if memTip == tl.TipMemRaw {
+		if memTip.HasCLayout() {
 			ptrTip = tl.TipPtrSRef
 		}
 		typeTip := typeTipRx.TipAt(i)
@@ -394,12 +425,10 @@ func (gen *Generator) getStructHelpers(goStructName []byte, cStructName string,
 	return
 }
 
-func (gen *Generator) getRawStructHelpers(goStructName []byte, cStructName string, spec tl.CType) (helpers []*Helper) {
-	if spec.GetPointers() > 0 {
-		return nil // can't addess a pointer receiver
-	}
+// getCastStructHelpers generates the helpers of the structs that have the layout of the C type,
+// the references are converted by a pointer cast.
+func (gen *Generator) getCastStructHelpers(goStructName []byte, spec tl.CType) (helpers []*Helper) {
 	cgoSpec := gen.tr.CGoSpec(spec, true)
-	structSpec := spec.(*tl.CStructSpec)
 
 	buf := new(bytes.Buffer)
 	fmt.Fprintf(buf, "func (x *%s) Ref() *%s", goStructName, cgoSpec)
@@ -469,13 +498,33 @@ func (gen *Generator) getRawStructHelpers(goStructName []byte, cStructName strin
 		Source:      buf.String(),
 		Requires:    []*Helper{allocHelper},
 	})
+	return helpers
+}
+
+func (gen *Generator) getRawStructHelpers(goStructName []byte, cStructName string, spec tl.CType) (helpers []*Helper) {
+	if spec.GetPointers() > 0 {
+		return nil // can't addess a pointer receiver
+	}
+	structSpec := spec.(*tl.CStructSpec)
+	helpers = gen.getCastStructHelpers(goStructName, spec)
+	buf := new(bytes.Buffer)
+
+	ptrTipRx, typeTipRx, memTipRx := gen.tr.TipRxsForSpec(tl.TipScopeType, cStructName, spec)
+	for i, m := range structSpec.Members {
+		if !m.IsBitField() || len(m.Name) == 0 {
+			continue
+		}
+		goSpec := gen.tr.TranslateSpec(m.Spec, ptrTipRx.TipAt(i), typeTipRx.TipAt(i))
+		const public = true
+		goName := "Get" + string(gen.tr.TransformName(tl.TargetType, m.Name, public))
+		helpers = append(helpers, gen.getBitFieldHelpers(goStructName, goName, m, goSpec, bitUnitRef(m))...)
+	}
 
 	if !gen.cfg.Options.StructAccessors {
 		return
 	}
-	ptrTipRx, typeTipRx, memTipRx := gen.tr.TipRxsForSpec(tl.TipScopeType, cStructName, spec)
 	for i, m := range structSpec.Members {
-		if len(m.Name) == 0 {
+		if len(m.Name) == 0 || m.IsBitField() {
 			continue
 		}
 		buf.Reset()
@@ -491,7 +540,7 @@ func (gen *Generator) getRawStructHelpers(goStructName []byte, cStructName strin
 			memTip = gen.MemTipOf(m)
 		}
 		ptrTip := ptrTipRx.TipAt(i)
-		if memTip == tl.TipMemRaw {
+		if memTip.HasCLayout() {
 			ptrTip = tl.TipPtrSRef
 		}
 		typeTip := typeTipRx.TipAt(i)
@@ -577,7 +626,7 @@ func (gen *Generator) getpassRefSource(goStructName []byte, cStructName string,
 			memTip = gen.MemTipOf(m)
 		}
 		ptrTip := ptrTipRx.TipAt(i)
-		if memTip == tl.TipMemRaw {
+		if memTip.HasCLayout() {
 			ptrTip = tl.TipPtrSRef
 		}
 		typeTip := typeTipRx.TipAt(i)
@@ -586,6 +635,13 @@ func (gen *Generator) getpassRefSource(goStructName []byte, cStructName string,
 		const public = true
 		// goName := "x." + string(gen.tr.TransformName(tl.TargetType, m.Name, public))
 		goName := "x." + "g" + string(gen.tr.TransformName(tl.TargetType, m.Name, public))
+		if m.IsBitField() {
+			// cgo can't reach bit-fields, so the bits are set through the Go struct
+			setterName := "Set" + string(gen.tr.TransformName(tl.TargetType, m.Name, public))
+			fmt.Fprintf(buf, "(*%s)(mem%2x).%s(%s)\n", goStructName, crc, setterName, goName)
+			fmt.Fprintf(buf, "%s = *new(%s)\n\n", goName, goSpec)
+			continue
+		}
 		fromProxy, nillable := gen.proxyValueFromGo(memTip, goName, goSpec, cgoSpec)
 		if nillable {
 			fmt.Fprintf(buf, "if %s != nil {\n", goName)
@@ -608,6 +664,10 @@ func (gen *Generator) getpassRefSource(goStructName []byte, cStructName string,
 	}
 	fmt.Fprintf(buf, "x.ref%2x = ref%2x\n", crc, crc)
 	fmt.Fprintf(buf, "x.allocs%2x = allocs%2x\n", crc, crc)
+	if gen.cfg.Options.Finalizers {
+		gen.submitHelper(gen.getTrackAllocsHelper())
+		fmt.Fprintf(buf, "trackAllocs(ref%2x, %q, allocs%2x)\n", crc, goStructName, crc)
+	}
 
 	writeSpace(buf, 1)
 	fmt.Fprintf(buf, "return ref%2x, allocs%2x\n", crc, crc)
@@ -637,7 +697,7 @@ func (gen *Generator) getNewStructSource(goStructName []byte, cStructName string
 			memTip = gen.MemTipOf(m)
 		}
 		ptrTip := ptrTipRx.TipAt(i)
-		if memTip == tl.TipMemRaw {
+		if memTip.HasCLayout() {
 			ptrTip = tl.TipPtrSRef
 		}
 		typeTip := typeTipRx.TipAt(i)
@@ -778,7 +838,7 @@ func (gen *Generator) getDerefSource(goStructName []byte, cStructName string, sp
 			memTip = gen.MemTipOf(m)
 		}
 		ptrTip := ptrTipRx.TipAt(i)
-		if memTip == tl.TipMemRaw {
+		if memTip.HasCLayout() {
 			ptrTip = tl.TipPtrSRef
 		}
 		typeTip := typeTipRx.TipAt(i)
@@ -792,3 +852,103 @@ func (gen *Generator) getDerefSource(goStructName []byte, cStructName string, sp
 	}
 	return buf.Bytes()
 }
+
+// bitStorage is the bytes of a struct that hold the bits of adjacent bit-fields,
+// the Go structs have those as byte arrays at the offsets of the C struct.
+type bitStorage struct {
+	offset int
+	size   int
+}
+
+func (st bitStorage) name() string {
+	return fmt.Sprintf("bitfield%d", st.offset)
+}
+
+// bitStoragesOf maps the first bit-field of each run of the adjacent ones to its storage.
+func bitStoragesOf(spec *tl.CStructSpec) map[*tl.CDecl]bitStorage {
+	storages := make(map[*tl.CDecl]bitStorage)
+	var first *tl.CDecl
+	var st bitStorage
+	for _, m := range spec.Members {
+		if !m.IsBitField() {
+			first = nil
+			continue
+		}
+		start, end := m.BitPos/8, (m.BitPos+m.BitWidth+7)/8
+		if first == nil || start > st.offset+st.size {
+			first, st = m, bitStorage{offset: start, size: end - start}
+		} else if end > st.offset+st.size {
+			st.size = end - st.offset
+		}
+		storages[first] = st
+	}
+	return storages
+}
+
+// writeBitFieldAlign writes a zero-sized field that aligns the Go struct as the C one,
+// since the storage of the bit-fields is made of bytes.
+func (gen *Generator) writeBitFieldAlign(wr io.Writer, spec *tl.CStructSpec) {
+	align := 1
+	for _, m := range spec.Members {
+		if !m.IsBitField() && m.Align > align {
+			align = m.Align
+		}
+	}
+	if spec.Align > align && spec.Align <= 8 {
+		fmt.Fprintf(wr, "_ [0]uint%d\n", spec.Align*8)
+	}
+}
+
+// bitUnitRef returns the pointer to the storage unit of the bit-field within the struct at x.
+func bitUnitRef(m *tl.CDecl) string {
+	return fmt.Sprintf("(*uint%d)(unsafe.Pointer(uintptr(unsafe.Pointer(x)) + %d))", m.BitUnit*8, m.Offset)
+}
+
+// getBitFieldHelpers generates a getter and a setter for the bit-field member,
+// the bits are masked and shifted within the storage unit pointed by unitRef.
+func (gen *Generator) getBitFieldHelpers(goStructName []byte, getterName string,
+	m *tl.CDecl, goSpec tl.GoTypeSpec, unitRef string) []*Helper {
+
+	const public = true
+	setterName := "Set" + string(gen.tr.TransformName(tl.TargetType, m.Name, public))
+	unitBits := m.BitUnit * 8
+	mask := uint64(1)<<uint(m.BitWidth) - 1
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "func (x *%s) %s() %s {\n", goStructName, getterName, goSpec)
+	fmt.Fprintf(buf, "unit := %s\n", unitRef)
+	switch {
+	case goSpec.Base == "bool":
+		fmt.Fprintf(buf, "return (*unit>>%d)&%#x != 0\n", m.BitOffset, mask)
+	case goSpec.Base == "int" && !goSpec.Unsigned && goSpec.Kind != tl.EnumKind:
+		// shift the bits up to the sign bit and back to extend the sign
+		fmt.Fprintf(buf, "return %s(int%d(*unit<<%d) >> %d)\n", goSpec, unitBits,
+			unitBits-m.BitOffset-m.BitWidth, unitBits-m.BitWidth)
+	default:
+		fmt.Fprintf(buf, "return %s((*unit >> %d) & %#x)\n", goSpec, m.BitOffset, mask)
+	}
+	buf.WriteRune('}')
+	getter := &Helper{
+		Name:        fmt.Sprintf("%s.%s", goStructName, getterName),
+		Description: fmt.Sprintf("%s returns the value of %s bit-field.", getterName, m.Name),
+		Source:      buf.String(),
+	}
+
+	buf = new(bytes.Buffer)
+	fmt.Fprintf(buf, "func (x *%s) %s(v %s) {\n", goStructName, setterName, goSpec)
+	fmt.Fprintf(buf, "unit := %s\n", unitRef)
+	if goSpec.Base == "bool" {
+		fmt.Fprintf(buf, "var bits uint%d\n", unitBits)
+		fmt.Fprintf(buf, "if v {\nbits = 1\n}\n")
+	} else {
+		fmt.Fprintf(buf, "bits := uint%d(v)\n", unitBits)
+	}
+	fmt.Fprintf(buf, "*unit = *unit&^(%#x<<%d) | (bits&%#x)<<%d\n", mask, m.BitOffset, mask, m.BitOffset)
+	buf.WriteRune('}')
+	setter := &Helper{
+		Name:        fmt.Sprintf("%s.%s", goStructName, setterName),
+		Description: fmt.Sprintf("%s sets the value of %s bit-field.", setterName, m.Name),
+		Source:      buf.String(),
+	}
+	return []*Helper{getter, setter}
+}
@@ -16,10 +16,12 @@ func (gen *Generator) writeTypeTypedef(wr io.Writer, decl *tl.CDecl, seenNames m
 	} else {
 		seenNames[string(goTypeName)] = true
 	}
+	gen.writeDocComment(wr, decl.Doc, true)
 	fmt.Fprintf(wr, "// %s type as declared in %s\n", goTypeName,
 		filepath.ToSlash(gen.tr.SrcLocation(tl.TargetType, decl.Name, decl.Pos)))
 	fmt.Fprintf(wr, "type %s %s", goTypeName, goSpec.UnderlyingString())
 	writeSpace(wr, 1)
+	gen.writeTypedDefines(wr, decl.Name)
 }
 
 func (gen *Generator) writeEnumTypedef(wr io.Writer, decl *tl.CDecl) {
@@ -30,14 +32,20 @@ func (gen *Generator) writeEnumTypedef(wr io.Writer, decl *tl.CDecl) {
 	goName := gen.tr.TransformName(tl.TargetType, cName)
 	typeRef := gen.tr.TranslateSpec(decl.Spec).UnderlyingString()
 	if typeName := string(goName); typeName != typeRef {
+		gen.writeDocComment(wr, decl.Doc, true)
 		fmt.Fprintf(wr, "// %s as declared in %s\n", goName,
 			filepath.ToSlash(gen.tr.SrcLocation(tl.TargetConst, cName, decl.Pos)))
 		fmt.Fprintf(wr, "type %s %s", goName, typeRef)
 		writeSpace(wr, 1)
 	}
+	gen.writeTypedDefines(wr, cName)
 }
 
 func (gen *Generator) writeFunctionTypedef(wr io.Writer, decl *tl.CDecl, seenNames map[string]bool) {
+	if gen.noCGO {
+		gen.writeFunctionPtrTypedef(wr, decl, seenNames)
+		return
+	}
 	var returnRef string
 	funcSpec := decl.Spec.Copy().(*tl.CFunctionSpec)
 	funcSpec.Pointers = 0 // function pointers not supported here
@@ -69,10 +77,11 @@ func (gen *Generator) writeFunctionTypedef(wr io.Writer, decl *tl.CDecl, seenNam
 	}
 	goSpec := gen.tr.TranslateSpec(funcSpec, ptrTipRx.Self(), typeTipRx.Self())
 	goSpec.Raw = "" // not used in func typedef
+	gen.writeDocComment(wr, decl.Doc, true)
 	fmt.Fprintf(wr, "// %s type as declared in %s\n", goFuncName,
 		filepath.ToSlash(gen.tr.SrcLocation(tl.TargetFunction, decl.Name, decl.Pos)))
 	fmt.Fprintf(wr, "type %s %s", goFuncName, goSpec)
-	gen.writeFunctionParams(wr, decl.Name, decl.Spec)
+	gen.writeFunctionParams(wr, decl.Name, decl.Spec, false)
 	if len(returnRef) > 0 {
 		fmt.Fprintf(wr, " %s", returnRef)
 	}
@@ -92,7 +101,15 @@ func getName(decl *tl.CDecl) (string, bool) {
 	return "", false
 }
 
-func (gen *Generator) writeStructTypedef(wr io.Writer, decl *tl.CDecl, raw bool, seenNames map[string]bool) {
+func (gen *Generator) writeStructTypedef(wr io.Writer, decl *tl.CDecl, memTip tl.Tip, seenNames map[string]bool) {
+	if gen.noCGO {
+		gen.writeLayoutStruct(wr, decl, seenNames)
+		return
+	}
+	if memTip == tl.TipMemMirror && decl.Spec.IsComplete() {
+		gen.writeMirrorStruct(wr, decl, seenNames)
+		return
+	}
 	cName, ok := getName(decl)
 	if !ok {
 		return
@@ -105,12 +122,14 @@ func (gen *Generator) writeStructTypedef(wr io.Writer, decl *tl.CDecl, raw bool,
 	} else {
 		seenNames[string(goName)] = true
 	}
-	if raw || !decl.Spec.IsComplete() {
+	if memTip == tl.TipMemRaw || !decl.Spec.IsComplete() {
 		// opaque struct
+		gen.writeDocComment(wr, decl.Doc, true)
 		fmt.Fprintf(wr, "// %s as declared in %s\n", goName,
 			filepath.ToSlash(gen.tr.SrcLocation(tl.TargetType, cName, decl.Pos)))
 		fmt.Fprintf(wr, "type %s C.%s", goName, decl.Spec.CGoName())
 		writeSpace(wr, 1)
+		gen.writeLayoutConsts(wr, goName, decl.Spec.(*tl.CStructSpec))
 		for _, helper := range gen.getRawStructHelpers(goName, cName, decl.Spec) {
 			gen.submitHelper(helper)
 		}
@@ -132,6 +151,7 @@ func (gen *Generator) writeStructTypedef(wr io.Writer, decl *tl.CDecl, raw bool,
 	}
 
 	// if decl.Spec.CGoName() == cName {
+	gen.writeDocComment(wr, decl.Doc, false)
 	fmt.Fprintf(wr, "type %s struct {", goName)
 	writeSpace(wr, 1)
 	gen.submitHelper(cgoAllocMap)
@@ -139,10 +159,15 @@ func (gen *Generator) writeStructTypedef(wr io.Writer, decl *tl.CDecl, raw bool,
 	writeEndStruct(wr)
 	writeSpace(wr, 1)
 	// }
+	gen.writeLayoutConsts(wr, goName, decl.Spec.(*tl.CStructSpec))
 
 }
 
 func (gen *Generator) writeUnionTypedef(wr io.Writer, decl *tl.CDecl) {
+	if gen.noCGO {
+		gen.writeLayoutUnion(wr, decl)
+		return
+	}
 	cName, ok := getName(decl)
 	if !ok {
 		return
@@ -151,11 +176,20 @@ func (gen *Generator) writeUnionTypedef(wr io.Writer, decl *tl.CDecl) {
 	typeRef := gen.tr.TranslateSpec(decl.Spec).UnderlyingString()
 
 	if typeName := string(goName); typeName != typeRef {
+		gen.writeDocComment(wr, decl.Doc, true)
 		fmt.Fprintf(wr, "// %s as declared in %s\n", goName,
 			filepath.ToSlash(gen.tr.SrcLocation(tl.TargetType, cName, decl.Pos)))
-		fmt.Fprintf(wr, "const sizeof%s = unsafe.Sizeof(C.%s{})\n", goName, decl.Spec.CGoName())
-		fmt.Fprintf(wr, "type %s [sizeof%s]byte\n", goName, goName)
+		spec := decl.Spec.(*tl.CStructSpec)
+		sizeName := fmt.Sprintf("sizeof%s", goName)
+		if gen.cfg.Options.LayoutChecks && spec.Size > 0 {
+			// the size is written along with the layout constants
+			sizeName = fmt.Sprintf("Sizeof%s", goName)
+		} else {
+			fmt.Fprintf(wr, "const %s = unsafe.Sizeof(C.%s{})\n", sizeName, decl.Spec.CGoName())
+		}
+		fmt.Fprintf(wr, "type %s [%s]byte\n", goName, sizeName)
 		writeSpace(wr, 1)
+		gen.writeLayoutConsts(wr, goName, spec)
 		return
 	}
 }
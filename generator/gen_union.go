@@ -1,7 +1,136 @@
 package generator
 
-import "io"
+import (
+	"fmt"
+	"io"
+
+	tl "github.com/xlab/c-for-go/translator"
+)
 
 func (gen *Generator) WriteUnions(wr io.Writer) int {
-	return 0
+	var count int
+	for _, decl := range gen.unionDecls() {
+		if gen.writeUnionAccessors(wr, decl) {
+			writeSpace(wr, 1)
+			count++
+		}
+	}
+	return count
+}
+
+// unionDecls collects the union declarations in the same order and
+// with the same deduplication rules as used by WriteTypedefs.
+func (gen *Generator) unionDecls() []*tl.CDecl {
+	var unions []*tl.CDecl
+	typedefs := gen.tr.Typedefs()
+	seenUnionTags := make(map[string]bool, len(typedefs))
+	seenUnionNames := make(map[string]bool, len(typedefs))
+	for _, decl := range typedefs {
+		if decl.Spec.Kind() != tl.UnionKind {
+			continue
+		} else if !gen.tr.IsAcceptableName(tl.TargetType, decl.Name) {
+			continue
+		}
+		if len(decl.Name) > 0 {
+			if seenUnionNames[decl.Name] {
+				continue
+			}
+			seenUnionNames[decl.Name] = true
+		}
+		if tag := decl.Spec.GetTag(); len(tag) > 0 {
+			if len(decl.Name) == 0 || decl.Name == tag {
+				if seenUnionTags[tag] {
+					continue
+				}
+			}
+			seenUnionTags[tag] = true
+		}
+		unions = append(unions, decl)
+	}
+	for _, def := range sortedTagDefs(gen.tr.TagMap()) {
+		if def.tagDecl.Spec.Kind() != tl.UnionKind {
+			continue
+		} else if seenUnionTags[def.tagName] {
+			continue
+		}
+		if !gen.tr.IsAcceptableName(tl.TargetPublic, def.tagName) {
+			continue
+		} else if !gen.tr.IsAcceptableName(tl.TargetType, def.tagName) {
+			continue
+		}
+		unions = append(unions, def.tagDecl)
+	}
+	return unions
+}
+
+// writeUnionAccessors writes As* and Set* methods for each member of the union,
+// the union itself is represented as a byte array sized after the C union.
+func (gen *Generator) writeUnionAccessors(wr io.Writer, decl *tl.CDecl) bool {
+	cName, ok := getName(decl)
+	if !ok {
+		return false
+	}
+	spec, ok := decl.Spec.(*tl.CStructSpec)
+	if !ok || !spec.IsComplete() {
+		return false
+	}
+	goName := gen.tr.TransformName(tl.TargetType, cName)
+	if typeRef := gen.tr.TranslateSpec(decl.Spec).UnderlyingString(); string(goName) == typeRef {
+		// no byte array has been declared by writeUnionTypedef
+		return false
+	}
+
+	var written bool
+	for _, m := range spec.Members {
+		if len(m.Name) == 0 {
+			continue
+		}
+		memberRef, ok := gen.unionMemberRef(m)
+		if !ok {
+			continue
+		}
+		const public = true
+		memberName := gen.tr.TransformName(tl.TargetType, m.Name, public)
+		written = true
+		fmt.Fprintf(wr, "// As%s returns a pointer to the %s member of the union.\n", memberName, m.Name)
+		fmt.Fprintf(wr, "func (u *%s) As%s() *%s", goName, memberName, memberRef)
+		fmt.Fprintf(wr, `{
+			return (*%s)(unsafe.Pointer(u))
+		}`, memberRef)
+		writeSpace(wr, 2)
+		fmt.Fprintf(wr, "// Set%s sets the %s member of the union.\n", memberName, m.Name)
+		fmt.Fprintf(wr, "func (u *%s) Set%s(v %s)", goName, memberName, memberRef)
+		fmt.Fprintf(wr, `{
+			*(*%s)(unsafe.Pointer(u)) = v
+		}`, memberRef)
+		writeSpace(wr, 2)
+	}
+	return written
+}
+
+// unionMemberRef returns a Go type reference for the union member that shares
+// the memory layout with the C member, so it can be accessed by a pointer cast.
+func (gen *Generator) unionMemberRef(m *tl.CDecl) (string, bool) {
+	switch m.Spec.Kind() {
+	case tl.StructKind, tl.OpaqueStructKind, tl.UnionKind, tl.EnumKind:
+		base := m.Spec.GetBase()
+		if len(base) == 0 {
+			// an anonymous type has no Go counterpart
+			return "", false
+		}
+		if !gen.tr.IsAcceptableName(tl.TargetType, base) {
+			return gen.tr.CGoSpec(m.Spec, false).String(), true
+		}
+	case tl.FunctionKind:
+		return gen.tr.CGoSpec(m.Spec, false).String(), true
+	}
+	goSpec := gen.tr.TranslateSpec(m.Spec, tl.TipPtrSRef, tl.TipTypeNamed)
+	switch {
+	case goSpec.Slices > 0:
+		return "", false
+	case goSpec.Base == "string", goSpec.Kind == tl.FunctionKind:
+		// Go strings and funcs don't share the layout with C
+		return gen.tr.CGoSpec(m.Spec, false).String(), true
+	}
+	return goSpec.String(), true
 }
@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	tl "github.com/xlab/c-for-go/translator"
+)
+
+// writeVariadicShims writes bindings for the fixed-arity shims of a variadic function,
+// the C sources of the shims are submitted as helpers.
+func (gen *Generator) writeVariadicShims(wr io.Writer, decl *tl.CDecl, public bool) int {
+	var count int
+	shims := gen.cfg.VariadicShims[decl.Name]
+	if len(shims) == 0 {
+		gen.tr.Diagnostics().Add(tl.DiagSkipped, decl.Pos, decl.Name,
+			"variadic function has no VariadicShims configured")
+	}
+	for _, shim := range shims {
+		shimDecl := gen.variadicShimDecl(decl, shim)
+		for _, helper := range gen.getVariadicShimHelpers(decl.Name, shimDecl) {
+			gen.submitHelper(helper)
+		}
+		ptrTip, typeTip := gen.functionTips(shimDecl.Name)
+		gen.writeFunctionDeclaration(wr, shimDecl, ptrTip, typeTip, public, false)
+		writeSpace(wr, 1)
+		count++
+	}
+	return count
+}
+
+// variadicShimDecl declares a shim function that takes the fixed params
+// of the variadic function followed by the args listed in the shim.
+func (gen *Generator) variadicShimDecl(decl *tl.CDecl, shim VariadicShim) *tl.CDecl {
+	spec := decl.Spec.(*tl.CFunctionSpec)
+	shimSpec := &tl.CFunctionSpec{
+		Raw:    shim.Name,
+		Return: spec.Return,
+	}
+	shimSpec.Params = append(shimSpec.Params, spec.Params...)
+	for i, arg := range shim.Args {
+		// arg types have been validated upon generator creation
+		argSpec, _ := gen.tr.ParseTypeName(arg)
+		shimSpec.Params = append(shimSpec.Params, &tl.CDecl{
+			Name: fmt.Sprintf("arg%d", len(spec.Params)+i),
+			Spec: argSpec,
+			Pos:  decl.Pos,
+		})
+	}
+	return &tl.CDecl{
+		Name: shim.Name,
+		Spec: shimSpec,
+		Pos:  decl.Pos,
+	}
+}
+
+func (gen *Generator) getVariadicShimHelpers(cFuncName string, shimDecl *tl.CDecl) (helpers []*Helper) {
+	spec := shimDecl.Spec.(*tl.CFunctionSpec)
+	var params []string
+	var paramNames []string
+	for _, param := range spec.Params {
+		paramSpec := gen.tr.NormalizeSpecPointers(param.Spec)
+		params = append(params, fmt.Sprintf("%s %s", paramSpec.AtLevel(0), param.Name))
+		paramNames = append(paramNames, param.Name)
+	}
+	paramList := strings.Join(params, ", ")
+	paramNamesList := strings.Join(paramNames, ", ")
+
+	buf := new(bytes.Buffer)
+	retSpec := "void"
+	if spec.Return != nil {
+		retSpec = gen.tr.NormalizeSpecPointers(spec.Return).String()
+	}
+	fmt.Fprintf(buf, "%s %s(%s);", retSpec, shimDecl.Name, paramList)
+	helpers = append(helpers, &Helper{
+		Name:        shimDecl.Name,
+		Description: fmt.Sprintf("%s is a fixed-arity shim for variadic %s.", shimDecl.Name, cFuncName),
+		Source:      buf.String(),
+		Side:        CHSide,
+	})
+
+	var ret string
+	if spec.Return != nil {
+		ret = "return "
+	}
+	buf = new(bytes.Buffer)
+	fmt.Fprintf(buf, "%s %s(%s) {\n", retSpec, shimDecl.Name, paramList)
+	fmt.Fprintf(buf, "\t%s%s(%s);\n", ret, cFuncName, paramNamesList)
+	buf.WriteRune('}')
+	helpers = append(helpers, &Helper{
+		Name:   shimDecl.Name,
+		Source: buf.String(),
+		Side:   CCSide,
+	})
+	return
+}
@@ -2,8 +2,10 @@ package generator
 
 import (
 	"errors"
+	"fmt"
 	"io"
 	"math/rand"
+	"regexp"
 	"sort"
 
 	tl "github.com/xlab/c-for-go/translator"
@@ -19,7 +21,20 @@ type Generator struct {
 	helpersChan   chan *Helper
 	rand          *rand.Rand
 	noTimestamps  bool
+	noCGO         bool
 	maxMem        MemSpec
+
+	typedDefinesSeen map[string]bool
+	flagEnumRxs      []*regexp.Regexp
+	// mirrorStructs are the structs written with the mirror mem tip.
+	mirrorStructs []*tl.CDecl
+	// layoutChecks are the checks of the struct layouts written after the typedefs.
+	layoutChecks []string
+	// tracksAllocs is set once the struct wrappers track their allocations with finalizers.
+	tracksAllocs bool
+	// owned and releasedBy are collected by ownedTypes.
+	owned      map[string]bool
+	releasedBy map[string]string
 }
 
 func (g *Generator) DisableTimestamps() {
@@ -41,12 +56,39 @@ type Config struct {
 	SysIncludes        []string         `yaml:"SysIncludes"`
 	Includes           []string         `yaml:"Includes"`
 	Options            GenOptions       `yaml:"Options"`
+	// VariadicShims lists fixed-arity shims to instantiate for each variadic C function.
+	VariadicShims map[string][]VariadicShim `yaml:"VariadicShims"`
+	// FlagEnums lists regexps of the C enum names to treat as bit-flags
+	// in addition to the ones detected by their values.
+	FlagEnums []string `yaml:"FlagEnums"`
+}
+
+// VariadicShim describes a C function with fixed arguments that calls a variadic function,
+// Args are C type names of the arguments passed in place of the ellipsis.
+type VariadicShim struct {
+	Name string   `yaml:"name"`
+	Args []string `yaml:"args"`
 }
 
 type GenOptions struct {
 	SafeStrings     bool `yaml:"SafeStrings"`
 	StructAccessors bool `yaml:"StructAccessors"`
 	KeepAlive       bool `yaml:"KeepAlive"`
+	DoxygenDocs     bool `yaml:"DoxygenDocs"`
+	EnumStringers   bool `yaml:"EnumStringers"`
+	EnumHelpers     bool `yaml:"EnumHelpers"`
+	// Finalizers makes the C memory allocated for the members of struct wrappers to be
+	// released once the wrapper is unreachable, unless it's released by Free before.
+	Finalizers bool `yaml:"Finalizers"`
+	// AllocHooks makes the package count the C memory allocated for the calls and report
+	// each allocation and release to the function set by SetAllocHook.
+	AllocHooks bool `yaml:"AllocHooks"`
+	// Arenas adds the variants of the functions returned by With that allocate the C memory
+	// of the arguments from an Arena, so that the memory is reused across the calls.
+	Arenas bool `yaml:"Arenas"`
+	// LayoutChecks makes the package export the size, the alignment and the member offsets of the
+	// structs and unions as computed for the target, along with the compile-time checks of those.
+	LayoutChecks bool `yaml:"LayoutChecks"`
 }
 
 func New(pkg string, cfg *Config, tr *tl.Translator) (*Generator, error) {
@@ -55,6 +97,26 @@ func New(pkg string, cfg *Config, tr *tl.Translator) (*Generator, error) {
 	} else if tr == nil {
 		return nil, errors.New("no translator provided")
 	}
+	for name, shims := range cfg.VariadicShims {
+		for _, shim := range shims {
+			if len(shim.Name) == 0 {
+				return nil, fmt.Errorf("no name provided for a variadic shim of %s", name)
+			}
+			for _, arg := range shim.Args {
+				if _, err := tr.ParseTypeName(arg); err != nil {
+					return nil, fmt.Errorf("variadic shim %s: %v", shim.Name, err)
+				}
+			}
+		}
+	}
+	var flagEnumRxs []*regexp.Regexp
+	for _, expr := range cfg.FlagEnums {
+		rx, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("flag enums: invalid regexp %s", expr)
+		}
+		flagEnumRxs = append(flagEnumRxs, rx)
+	}
 	gen := &Generator{
 		pkg: pkg,
 		cfg: cfg,
@@ -65,6 +127,9 @@ func New(pkg string, cfg *Config, tr *tl.Translator) (*Generator, error) {
 		doneC:       make(chan struct{}),
 		rand:        rand.New(rand.NewSource(+79269965690)),
 		maxMem:      MemSpecDefault,
+
+		typedDefinesSeen: make(map[string]bool),
+		flagEnumRxs:      flagEnumRxs,
 	}
 	return gen, nil
 }
@@ -84,8 +149,16 @@ func (s declList) Less(i, j int) bool {
 func (gen *Generator) WriteConst(wr io.Writer) int {
 	var count int
 	if defines := gen.tr.Defines(); len(defines) > 0 {
-		n := gen.writeDefinesGroup(wr, defines)
-		count = count + n
+		untyped, _, typeNames := groupDefines(defines)
+		if len(untyped) > 0 {
+			n := gen.writeDefinesGroup(wr, untyped, "")
+			count = count + n
+		}
+		for _, typeName := range typeNames {
+			if !gen.hasTypeDecl(typeName) {
+				count += gen.writeTypedDefines(wr, typeName)
+			}
+		}
 	}
 	writeSpace(wr, 1)
 	tagsSeen := make(map[string]bool)
@@ -115,6 +188,7 @@ func (gen *Generator) WriteConst(wr io.Writer) int {
 			continue
 		}
 		if !gen.tr.IsAcceptableName(tl.TargetType, tag) {
+			gen.reportFiltered(decl, tag)
 			continue
 		}
 		enumList = append(enumList, decl)
@@ -133,6 +207,7 @@ func (gen *Generator) WriteConst(wr io.Writer) int {
 			continue
 		}
 		if !gen.tr.IsAcceptableName(tl.TargetType, decl.Name) {
+			gen.reportFiltered(decl, decl.Name)
 			continue
 		}
 		if expandEnum(decl) {
@@ -146,6 +221,7 @@ func (gen *Generator) WriteConst(wr io.Writer) int {
 			}
 			if len(decl.Name) > 0 {
 				if !gen.tr.IsAcceptableName(tl.TargetType, decl.Name) {
+					gen.reportFiltered(decl, decl.Name)
 					continue
 				}
 			}
@@ -166,6 +242,7 @@ func (gen *Generator) WriteConst(wr io.Writer) int {
 			continue
 		}
 		if !gen.tr.IsAcceptableName(tl.TargetPublic, decl.Name) {
+			gen.reportFiltered(decl, decl.Name)
 			continue
 		}
 		gen.writeConstDeclaration(wr, decl)
@@ -196,6 +273,7 @@ func (gen *Generator) WriteTypedefs(wr io.Writer) int {
 	seenFunctionNames := make(map[string]bool, len(typedefs))
 	for _, decl := range typedefs {
 		if !gen.tr.IsAcceptableName(tl.TargetType, decl.Name) {
+			gen.reportFiltered(decl, decl.Name)
 			continue
 		}
 		switch decl.Spec.Kind() {
@@ -208,8 +286,7 @@ func (gen *Generator) WriteTypedefs(wr io.Writer) int {
 				}
 				seenStructTags[tag] = true
 			}
-			memTip := gen.MemTipOf(decl)
-			gen.writeStructTypedef(wr, decl, memTip == tl.TipMemRaw, seenStructNames)
+			gen.writeStructTypedef(wr, decl, gen.MemTipOf(decl), seenStructNames)
 		case tl.UnionKind:
 			if len(decl.Name) > 0 {
 				if seenUnionNames[decl.Name] {
@@ -249,14 +326,16 @@ func (gen *Generator) WriteTypedefs(wr io.Writer) int {
 				continue
 			}
 			if !gen.tr.IsAcceptableName(tl.TargetPublic, tag) {
+				gen.reportFiltered(decl, tag)
 				continue
 			} else if !gen.tr.IsAcceptableName(tl.TargetType, tag) {
+				gen.reportFiltered(decl, tag)
 				continue
 			}
 			if memTipRx, ok := gen.tr.MemTipRx(tag); ok {
-				gen.writeStructTypedef(wr, decl, memTipRx.Self() == tl.TipMemRaw, seenStructNames)
+				gen.writeStructTypedef(wr, decl, memTipRx.Self(), seenStructNames)
 			} else {
-				gen.writeStructTypedef(wr, decl, false, seenStructNames)
+				gen.writeStructTypedef(wr, decl, tl.NoTip, seenStructNames)
 			}
 			writeSpace(wr, 1)
 			count++
@@ -265,8 +344,10 @@ func (gen *Generator) WriteTypedefs(wr io.Writer) int {
 				continue
 			}
 			if !gen.tr.IsAcceptableName(tl.TargetPublic, tag) {
+				gen.reportFiltered(decl, tag)
 				continue
 			} else if !gen.tr.IsAcceptableName(tl.TargetType, tag) {
+				gen.reportFiltered(decl, tag)
 				continue
 			}
 			gen.writeUnionTypedef(wr, decl)
@@ -274,6 +355,7 @@ func (gen *Generator) WriteTypedefs(wr io.Writer) int {
 			count++
 		}
 	}
+	gen.writeLayoutChecks(wr)
 	return count
 }
 
@@ -291,6 +373,7 @@ func (gen *Generator) WriteDeclares(wr io.Writer) int {
 			if len(decl.Name) == 0 {
 				continue
 			} else if !gen.tr.IsAcceptableName(tl.TargetPublic, decl.Name) {
+				gen.reportFiltered(decl, decl.Name)
 				continue
 			} else if seenStructs[decl.Name] {
 				continue
@@ -302,6 +385,7 @@ func (gen *Generator) WriteDeclares(wr io.Writer) int {
 			if len(decl.Name) == 0 {
 				continue
 			} else if !gen.tr.IsAcceptableName(tl.TargetPublic, decl.Name) {
+				gen.reportFiltered(decl, decl.Name)
 				continue
 			} else if seenUnions[decl.Name] {
 				continue
@@ -312,6 +396,7 @@ func (gen *Generator) WriteDeclares(wr io.Writer) int {
 		case tl.EnumKind:
 			if !decl.Spec.IsComplete() {
 				if !gen.tr.IsAcceptableName(tl.TargetPublic, decl.Name) {
+					gen.reportFiltered(decl, decl.Name)
 					continue
 				} else if seenEnums[decl.Name] {
 					continue
@@ -322,26 +407,26 @@ func (gen *Generator) WriteDeclares(wr io.Writer) int {
 			}
 		case tl.FunctionKind:
 			if !gen.tr.IsAcceptableName(tl.TargetFunction, decl.Name) {
+				gen.reportFiltered(decl, decl.Name)
 				continue
 			} else if seenFunctions[decl.Name] {
 				continue
 			} else {
 				seenFunctions[decl.Name] = true
 			}
-			// defaults to ref for the returns
-			ptrTip := tl.TipPtrRef
-			if ptrTipRx, ok := gen.tr.PtrTipRx(tl.TipScopeFunction, decl.Name); ok {
-				if tip := ptrTipRx.Self(); tip.IsValid() {
-					ptrTip = tip
-				}
+			if gen.isReleaseFunc(decl) {
+				continue
 			}
-			typeTip := tl.TipTypeNamed
-			if typeTipRx, ok := gen.tr.TypeTipRx(tl.TipScopeFunction, decl.Name); ok {
-				if tip := typeTipRx.Self(); tip.IsValid() {
-					typeTip = tip
-				}
+			if decl.Spec.(*tl.CFunctionSpec).IsVariadic {
+				// cgo can't call variadic functions, only the shims are written
+				count += gen.writeVariadicShims(wr, decl, public)
+				continue
+			}
+			ptrTip, typeTip := gen.functionTips(decl.Name)
+			gen.writeFunctionDeclaration(wr, decl, ptrTip, typeTip, public, false)
+			if gen.cfg.Options.Arenas && gen.hasArenaArgs(decl) {
+				gen.writeFunctionDeclaration(wr, decl, ptrTip, typeTip, public, true)
 			}
-			gen.writeFunctionDeclaration(wr, decl, ptrTip, typeTip, public)
 		}
 		writeSpace(wr, 1)
 		count++
@@ -349,6 +434,29 @@ func (gen *Generator) WriteDeclares(wr io.Writer) int {
 	return count
 }
 
+// reportFiltered records a declaration that has been rejected by the accept rules.
+func (gen *Generator) reportFiltered(decl *tl.CDecl, name string) {
+	gen.tr.Diagnostics().Add(tl.DiagFiltered, decl.Pos, name, "rejected by the translator rules")
+}
+
+// functionTips returns the pointer and type tips set for the function itself.
+func (gen *Generator) functionTips(name string) (ptrTip, typeTip tl.Tip) {
+	// defaults to ref for the returns
+	ptrTip = tl.TipPtrRef
+	if ptrTipRx, ok := gen.tr.PtrTipRx(tl.TipScopeFunction, name); ok {
+		if tip := ptrTipRx.Self(); tip.IsValid() {
+			ptrTip = tip
+		}
+	}
+	typeTip = tl.TipTypeNamed
+	if typeTipRx, ok := gen.tr.TypeTipRx(tl.TipScopeFunction, name); ok {
+		if tip := typeTipRx.Self(); tip.IsValid() {
+			typeTip = tip
+		}
+	}
+	return ptrTip, typeTip
+}
+
 func (gen *Generator) Close() {
 	if gen.closed {
 		return
@@ -1,26 +1,34 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sync"
 	"time"
 
-	"github.com/tj/go-spin"
+	"github.com/xlab/c-for-go/translator"
 )
 
 var (
 	outputPath = flag.String("out", "", "Specify a `dir` for the output.")
-	noCGO      = flag.Bool("nocgo", false, "Do not include a cgo-specific header in resulting files.")
+	noCGO      = flag.Bool("nocgo", false, "Generate pure Go bindings that load the library at runtime, without cgo.")
 	ccDefs     = flag.Bool("ccdefs", false, "Use built-in defines from a hosted C-compiler.")
 	ccIncl     = flag.Bool("ccincl", false, "Use built-in sys include paths from a hosted C-compiler.")
 	maxMem     = flag.String("maxmem", "0x7fffffff", "Specifies platform's memory cap the generated code.")
 	fancy      = flag.Bool("fancy", true, "Enable fancy output in the term.")
 	nostamp    = flag.Bool("nostamp", false, "Disable printing timestamps in the output files.")
 	debug      = flag.Bool("debug", false, "Enable some debug info.")
+	verify     = flag.Bool("verify", false, "Type-check the generated Go code against the C headers.")
+	verifyStub = flag.Bool("verifystub", false, "Also build the generated package against a stub C library when verifying.")
+	jobs       = flag.Int("j", runtime.NumCPU(), "Process up to `n` package configs concurrently.")
+	diagPath   = flag.String("diag", "", "Write the diagnostics as JSON to the `file`, use - for stdout.")
+	noCache    = flag.Bool("nocache", false, "Regenerate the packages even if nothing has changed since the last run.")
 )
 
 const logo = `Copyright (c) 2015-2017 Maxim Kupriianov <max@kc.vc>
@@ -28,70 +36,181 @@ Based on a C99 compiler front end by Jan Mercl <0xjnml@gmail.com>
 `
 
 func init() {
-	if *debug {
-		log.SetFlags(log.Lshortfile)
-	} else {
-		log.SetFlags(0)
-	}
 	flag.Usage = func() {
-		fmt.Println(logo)
+		fmt.Print(logo)
+		fmt.Println()
 		fmt.Printf("Usage: %s package1.yml [package2.yml] ...\n", os.Args[0])
 		fmt.Println("See https://github.com/xlab/c-for-go for examples and documentation.")
 		fmt.Println("Options:")
 		flag.PrintDefaults()
 	}
+}
+
+func main() {
 	flag.Parse()
+	if *debug {
+		log.SetFlags(log.Lshortfile)
+	} else {
+		log.SetFlags(0)
+	}
 	if len(flag.Args()) == 0 {
 		flag.Usage()
 		fmt.Println()
 		log.Fatalln("[ERR] no package configuration files have been provided.")
 	}
-}
-
-func main() {
-	s := spin.New()
+	cfgPaths := getConfigPaths()
+	n := *jobs
+	if n < 1 {
+		n = 1
+	}
+	prog := newProgress(cfgPaths, *fancy)
+	stopProgress := prog.Start()
 
-	var wg sync.WaitGroup
-	doneChan := make(chan struct{})
-	for _, cfgPath := range getConfigPaths() {
-		if *fancy {
-			wg.Add(1)
-			go func() {
-				for {
-					select {
-					case <-doneChan:
-						doneChan = make(chan struct{})
-						fmt.Printf("\r  \033[36mprocessing %s\033[m done.\n", cfgPath)
-						wg.Done()
-						return
-					default:
-						fmt.Printf("\r  \033[36mprocessing %s\033[m %s", cfgPath, s.Next())
-						time.Sleep(100 * time.Millisecond)
-					}
-				}
-			}()
+	var (
+		wg      sync.WaitGroup
+		mux     sync.Mutex
+		errs    = make([]error, len(cfgPaths))
+		diags   = make([][]translator.Diagnostic, len(cfgPaths))
+		outputs = make(map[string]string)
+	)
+	// claimOutput makes sure that each package gets its own output dir
+	claimOutput := func(cfgPath, dir string) error {
+		mux.Lock()
+		defer mux.Unlock()
+		if owner, ok := outputs[dir]; ok {
+			return fmt.Errorf("output dir %s is already used by %s", dir, owner)
 		}
+		outputs[dir] = cfgPath
+		return nil
+	}
+	sem := make(chan struct{}, n)
+	for i, cfgPath := range cfgPaths {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, cfgPath string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			prog.Set(i, stateRunning)
+			state, list, err := runConfig(cfgPath, claimOutput)
+			diags[i] = list
+			if err != nil {
+				errs[i] = err
+				state = stateFailed
+			}
+			prog.Set(i, state)
+		}(i, cfgPath)
+	}
+	wg.Wait()
+	stopProgress()
 
-		var t0 time.Time
-		if *debug {
-			t0 = time.Now()
+	for i, list := range diags {
+		if len(list) == 0 {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "diagnostics for %s:\n", cfgPaths[i])
+		translator.WriteSummary(os.Stderr, list)
+	}
+	if len(*diagPath) > 0 {
+		if err := writeDiagnostics(*diagPath, cfgPaths, diags); err != nil {
+			log.Println("[ERR] cannot write the diagnostics:", err)
 		}
-		process, err := NewProcess(cfgPath, *outputPath)
+	}
+
+	var failed bool
+	for i, err := range errs {
 		if err != nil {
-			log.Fatalln("[ERR]", err)
+			log.Printf("[ERR] %s: %v", cfgPaths[i], err)
+			failed = true
+		}
+	}
+	if failed {
+		os.Exit(1)
+	}
+}
+
+// runConfig generates the package described by the config, the resulting
+// state is returned for the progress output along with the diagnostics.
+func runConfig(cfgPath string, claimOutput func(cfgPath, dir string) error) (string, []translator.Diagnostic, error) {
+	var cache *runCache
+	if !*noCache {
+		var err error
+		if cache, err = openRunCache(cfgPath, *outputPath); err != nil {
+			log.Printf("[WARN] %s: cache is not available: %v", cfgPath, err)
+		} else if cache.UpToDate() {
+			return stateUpToDate, cache.Diagnostics, nil
+		}
+	}
+
+	t0 := time.Now()
+	process, err := NewProcess(cfgPath, *outputPath)
+	if err != nil {
+		return "", nil, err
+	}
+	outputDir, err := filepath.Abs(filepath.Join(*outputPath, process.cfg.Generator.PackageName))
+	if err != nil {
+		return "", nil, err
+	}
+	if err := claimOutput(cfgPath, outputDir); err != nil {
+		return "", nil, err
+	}
+	process.Generate(*noCGO)
+	if err := process.Flush(*noCGO); err != nil {
+		return "", process.Diagnostics(), err
+	}
+	if cache != nil {
+		if err := cache.RemoveStale(process.Outputs()); err != nil {
+			log.Printf("[WARN] %s: cannot remove the stale outputs: %v", cfgPath, err)
 		}
-		process.Generate(*noCGO)
-		if err := process.Flush(*noCGO); err != nil {
-			log.Fatalln("[ERR]", err)
+	}
+	if *verify {
+		if *noCGO {
+			log.Printf("[WARN] %s: verification requires cgo, skipping", cfgPath)
+		} else if err := process.Verify(*verifyStub); err != nil {
+			return "", process.Diagnostics(), err
 		}
-		if *debug {
-			fmt.Printf("done in %v\n", time.Now().Sub(t0))
+	}
+	diags := process.Diagnostics()
+	if cache != nil {
+		cache.Diagnostics = diags
+		if err := cache.Save(process.Inputs(), process.Outputs()); err != nil {
+			log.Printf("[WARN] %s: cannot save the cache: %v", cfgPath, err)
 		}
-		if *fancy {
-			close(doneChan)
-			wg.Wait()
+	}
+	if *debug {
+		return fmt.Sprintf("%s (in %v)", stateDone, time.Since(t0)), diags, nil
+	}
+	return stateDone, diags, nil
+}
+
+// writeDiagnostics writes the diagnostics of each config as JSON, the path "-" stands for stdout.
+func writeDiagnostics(path string, cfgPaths []string, diags [][]translator.Diagnostic) error {
+	type configDiagnostics struct {
+		Config      string                  `json:"config"`
+		Diagnostics []translator.Diagnostic `json:"diagnostics"`
+	}
+	report := make([]configDiagnostics, 0, len(cfgPaths))
+	for i, cfgPath := range cfgPaths {
+		list := diags[i]
+		if list == nil {
+			list = []translator.Diagnostic{}
 		}
+		report = append(report, configDiagnostics{
+			Config:      cfgPath,
+			Diagnostics: list,
+		})
+	}
+	data, err := json.MarshalIndent(report, "", "\t")
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+	if path == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
 	}
+	return ioutil.WriteFile(path, data, 0644)
 }
 
 func getConfigPaths() (paths []string) {
@@ -3,21 +3,35 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"go/token"
 	"log"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
+	"sync"
 
 	"modernc.org/cc"
+	"modernc.org/xc"
 )
 
 type Config struct {
-	Arch         string   `yaml:"Arch"`
-	IncludePaths []string `yaml:"IncludePaths"`
-	SourcesPaths []string `yaml:"SourcesPaths"`
-	IgnoredPaths []string `yaml:"IgnoredPaths"`
+	Arch string `yaml:"Arch"`
+	// Arches lists the GOARCH values to generate the bindings for at once, the code
+	// that differs between them is written into the files with GOARCH suffixes.
+	Arches []string `yaml:"Arches"`
+	// Platforms are the named profiles to generate the bindings for at once, the code
+	// that differs between them is written into the files with GOOS suffixes.
+	Platforms []Platform `yaml:"Platforms"`
+	// OS adds the macros of the target OS, e.g. __linux__ or _WIN32.
+	OS string `yaml:"OS"`
+	// Model overrides the data model of the arch, i.e. llp64 for Windows.
+	Model        DataModel `yaml:"Model"`
+	IncludePaths []string  `yaml:"IncludePaths"`
+	SourcesPaths []string  `yaml:"SourcesPaths"`
+	IgnoredPaths []string  `yaml:"IgnoredPaths"`
 
 	Defines map[string]interface{} `yaml:"Defines"`
 
@@ -26,19 +40,112 @@ type Config struct {
 	archBits TargetArch
 }
 
+// parseMu serializes the parsing, cc keeps its files and tokens in the process-wide
+// xc.FileSet and xc.Dict tables and is not meant to be used concurrently.
+var parseMu sync.Mutex
+
 func ParseWith(cfg *Config) (*cc.TranslationUnit, error) {
+	unit, _, err := ParseWithSources(cfg)
+	return unit, err
+}
+
+// ParseWithSources is like ParseWith but also returns the absolute paths of all files
+// that have been read, including the headers pulled in by the #include directives.
+func ParseWithSources(cfg *Config) (*cc.TranslationUnit, []string, error) {
 	if len(cfg.SourcesPaths) == 0 {
-		return nil, errors.New("parser: no target paths specified")
+		return nil, nil, errors.New("parser: no target paths specified")
 	}
 	cfg, err := checkConfig(cfg)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	predefined := predefines(cfg)
+	parseMu.Lock()
+	defer parseMu.Unlock()
+	// cc memoizes the files by path for the lifetime of the process, so the files
+	// changed since a previous parse would not be read again nor reported as sources
+	xc.Files = xc.NewFileCentral()
+	base := xc.FileSet.Base()
+	unit, err := parse(cfg, predefined)
+	if err != nil {
+		return nil, nil, err
+	}
+	return unit, filesSince(base), nil
+}
+
+func parse(cfg *Config, predefined string) (*cc.TranslationUnit, error) {
+	// cc.Parse marks the model as initialized, so it can't be shared between runs
+	model := *models[cfg.archBits]
+	if m, ok := dataModels[cfg.Model]; ok {
+		model = *m
+	}
+	return cc.Parse(predefined, cfg.SourcesPaths, &model,
+		cc.SysIncludePaths(cfg.IncludePaths),
+		cc.EnableAnonymousStructFields(),
+		cc.EnableAsm(),
+		cc.EnableAlternateKeywords(),
+		cc.EnableIncludeNext(),
+		cc.EnableNoreturn(),
+		cc.EnableEmptyDeclarations(),
+		cc.EnableWideEnumValues(),
+		cc.EnableWideBitFieldTypes(),
+
+		cc.AllowCompatibleTypedefRedefinitions(),
+	)
+}
+
+// Predefined returns the predefined macros the sources of the config would be
+// parsed with, the config itself is left intact.
+func Predefined(cfg *Config) (string, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	cfgCopy := *cfg
+	cfgCopy.IncludePaths = append([]string(nil), cfg.IncludePaths...)
+	cfgCopy.SourcesPaths = append([]string(nil), cfg.SourcesPaths...)
+	c, err := checkConfig(&cfgCopy)
+	if err != nil {
+		return "", err
+	}
+	return predefines(c), nil
+}
+
+// filesSince returns the absolute paths of the files added to xc.FileSet
+// starting from the base offset.
+func filesSince(base int) []string {
+	var files []string
+	seen := make(map[string]bool)
+	xc.FileSet.Iterate(func(f *token.File) bool {
+		if f.Base() < base {
+			return true
+		}
+		path, err := filepath.Abs(f.Name())
+		if err != nil || seen[path] {
+			return true
+		}
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			seen[path] = true
+			files = append(files, path)
+		}
+		return true
+	})
+	sort.Strings(files)
+	return files
+}
+
+// predefines returns the macros defined before parsing the sources,
+// the include paths of cfg are amended if the host's paths were requested.
+func predefines(cfg *Config) string {
 	predefined := builtinBase
 	// user-provided defines take precedence
-	for name, value := range cfg.Defines {
-		switch v := value.(type) {
+	names := make([]string, 0, len(cfg.Defines))
+	for name := range cfg.Defines {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch v := cfg.Defines[name].(type) {
 		case string:
 			predefined += fmt.Sprintf("\n#define %s \"%s\"", name, v)
 		case int, int16, int32, int64, uint, uint16, uint32, uint64:
@@ -81,14 +188,17 @@ func ParseWith(cfg *Config) (*cc.TranslationUnit, error) {
 		predefined += fmt.Sprintf("\n%s", ccDefs)
 	} else {
 		predefined += basePredefines
-		if archDefs, ok := archPredefines[cfg.archBits]; ok {
+		if archDefs := archPredefinesFor(cfg); len(archDefs) > 0 {
 			predefined += fmt.Sprintf("\n%s", archDefs)
 		}
+		if osDefs := osPredefinesFor(cfg); len(osDefs) > 0 {
+			predefined += fmt.Sprintf("\n%s", osDefs)
+		}
 	}
 	// undefines?
 	predefined += fmt.Sprintf("\n%s", builtinBaseUndef)
-	for name, value := range cfg.Defines {
-		switch value.(type) {
+	for _, name := range names {
+		switch value := cfg.Defines[name]; value.(type) {
 		case string, int, int16, int32, int64, uint, uint16, uint32, uint64, float32, float64:
 			continue
 		default: // a corner case: undef using an the nil value
@@ -97,33 +207,27 @@ func ParseWith(cfg *Config) (*cc.TranslationUnit, error) {
 			}
 		}
 	}
-	model := models[cfg.archBits]
-	return cc.Parse(predefined, cfg.SourcesPaths, model,
-		cc.SysIncludePaths(cfg.IncludePaths),
-		cc.EnableAnonymousStructFields(),
-		cc.EnableAsm(),
-		cc.EnableAlternateKeywords(),
-		cc.EnableIncludeNext(),
-		cc.EnableNoreturn(),
-		cc.EnableEmptyDeclarations(),
-		cc.EnableWideEnumValues(),
-		cc.EnableWideBitFieldTypes(),
-
-		cc.AllowCompatibleTypedefRedefinitions(),
-	)
+	return predefined
 }
 
 func checkConfig(cfg *Config) (*Config, error) {
 	if cfg == nil {
 		cfg = &Config{}
 	}
-	if arch, ok := arches[cfg.Arch]; !ok {
-		// default to 64-bit arch
-		cfg.archBits = Arch64
-	} else if arch != Arch32 && arch != Arch64 && arch != Arch48 {
+	if arch, ok := arches[cfg.Arch]; ok {
+		cfg.archBits = arch
+	} else if _, ok := models[TargetArch(cfg.Arch)]; ok {
+		cfg.archBits = TargetArch(cfg.Arch)
+	} else {
 		// default to 64-bit arch
 		cfg.archBits = Arch64
 	}
+	if _, ok := dataModels[cfg.Model]; !ok && len(cfg.Model) > 0 {
+		return nil, fmt.Errorf("parser: unknown data model %s", cfg.Model)
+	}
+	if _, ok := osPredefines[cfg.OS]; !ok && len(cfg.OS) > 0 {
+		return nil, fmt.Errorf("parser: unknown OS %s", cfg.OS)
+	}
 	// workaround for cznic's cc (it panics if supplied path is a dir)
 	var saneFiles []string
 	for _, path := range cfg.SourcesPaths {
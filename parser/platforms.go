@@ -0,0 +1,188 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"modernc.org/cc"
+)
+
+// Platform is a named profile of the parser config, the name is the GOOS value
+// the bindings parsed with the profile are built for.
+type Platform struct {
+	Name string `yaml:"Name"`
+	Arch string `yaml:"Arch"`
+	// Arches are used instead of Arch to generate the bindings for several arches
+	// of the platform, the files get both GOOS and GOARCH suffixes.
+	Arches []string  `yaml:"Arches"`
+	Model  DataModel `yaml:"Model"`
+	// IncludePaths are the paths to the headers vendored for the platform,
+	// the relative paths are resolved against the directory of the config.
+	IncludePaths []string `yaml:"IncludePaths"`
+
+	Defines map[string]interface{} `yaml:"Defines"`
+}
+
+// osPredefines are the macros the compilers define for the target OS.
+var osPredefines = map[string]string{
+	"linux": strings.Join([]string{
+		`#define __linux__ 1`,
+		`#define __linux 1`,
+		`#define __gnu_linux__ 1`,
+		unix,
+	}, "\n"),
+	"android": strings.Join([]string{
+		`#define __ANDROID__ 1`,
+		`#define __linux__ 1`,
+		`#define __linux 1`,
+		unix,
+	}, "\n"),
+	"darwin": strings.Join([]string{
+		`#define __APPLE__ 1`,
+		`#define __MACH__ 1`,
+	}, "\n"),
+	"ios": strings.Join([]string{
+		`#define __APPLE__ 1`,
+		`#define __MACH__ 1`,
+	}, "\n"),
+	"freebsd": strings.Join([]string{
+		`#define __FreeBSD__ 1`,
+		unix,
+	}, "\n"),
+	"netbsd": strings.Join([]string{
+		`#define __NetBSD__ 1`,
+		unix,
+	}, "\n"),
+	"openbsd": strings.Join([]string{
+		`#define __OpenBSD__ 1`,
+		unix,
+	}, "\n"),
+	"windows": `#define _WIN32 1`,
+}
+
+const unix = "#define __unix__ 1\n#define __unix 1"
+
+// osPredefinesFor returns the OS macros, _WIN64 is added for the 64-bit Windows.
+func osPredefinesFor(cfg *Config) string {
+	defs, ok := osPredefines[cfg.OS]
+	if !ok {
+		return ""
+	}
+	if cfg.OS == "windows" && models[cfg.archBits].Items[cc.Ptr].Size == 8 {
+		defs += "\n#define _WIN64 1"
+	}
+	return defs
+}
+
+// Target is a config derived from the arches and platforms listed in the parser config,
+// GOOS and GOARCH are set if the bindings parsed with it are specific to those.
+type Target struct {
+	GOOS   string
+	GOARCH string
+	Config *Config
+}
+
+// Suffix returns the GOOS and GOARCH part of the names of the files the target is generated into.
+func (t Target) Suffix() string {
+	switch {
+	case len(t.GOOS) == 0:
+		return t.GOARCH
+	case len(t.GOARCH) == 0:
+		return t.GOOS
+	default:
+		return t.GOOS + "_" + t.GOARCH
+	}
+}
+
+// Targets returns a config per arch listed in Arches or per platform listed in Platforms,
+// or nothing if none are listed. The platform defines override the ones of the config, its
+// include paths are looked up first. The platforms are parsed with the headers vendored
+// for them, so the host's macros and include paths are not used.
+func Targets(cfg *Config) ([]Target, error) {
+	if len(cfg.Arches) > 0 && len(cfg.Platforms) > 0 {
+		return nil, fmt.Errorf("parser: Arches and Platforms cannot be used together, list the arches in the platforms")
+	}
+	var targets []Target
+	seen := make(map[string]bool)
+	add := func(target Target) error {
+		if suffix := target.Suffix(); seen[suffix] {
+			return fmt.Errorf("parser: target %s is listed twice", suffix)
+		} else {
+			seen[suffix] = true
+		}
+		targets = append(targets, target)
+		return nil
+	}
+	for _, arch := range cfg.Arches {
+		target := cfg.copy()
+		target.Arch = arch
+		if err := add(Target{GOARCH: arch, Config: target}); err != nil {
+			return nil, err
+		}
+	}
+	for _, platform := range cfg.Platforms {
+		if _, ok := osPredefines[platform.Name]; !ok {
+			return nil, fmt.Errorf("parser: unknown platform %s", platform.Name)
+		}
+		if len(platform.Arch) > 0 && len(platform.Arches) > 0 {
+			return nil, fmt.Errorf("parser: platform %s has both Arch and Arches", platform.Name)
+		}
+		target := cfg.copy()
+		target.OS = platform.Name
+		target.CCDefs = false
+		target.CCIncl = false
+		target.IncludePaths = append(append([]string(nil), platform.IncludePaths...), cfg.IncludePaths...)
+		if len(platform.Model) > 0 {
+			target.Model = platform.Model
+		}
+		for name, value := range platform.Defines {
+			target.Defines[name] = value
+		}
+		if len(platform.Arches) == 0 {
+			target.Arch = platform.Arch
+			if err := add(Target{GOOS: platform.Name, Config: target.forPlatform()}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for _, arch := range platform.Arches {
+			archTarget := target.copy()
+			archTarget.Arch = arch
+			err := add(Target{GOOS: platform.Name, GOARCH: arch, Config: archTarget.forPlatform()})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return targets, nil
+}
+
+// copy returns a copy of the config without the arches and platforms listed.
+func (cfg *Config) copy() *Config {
+	c := *cfg
+	c.Arches = nil
+	c.Platforms = nil
+	c.IncludePaths = append([]string(nil), cfg.IncludePaths...)
+	c.SourcesPaths = append([]string(nil), cfg.SourcesPaths...)
+	c.Defines = make(map[string]interface{}, len(cfg.Defines))
+	for name, value := range cfg.Defines {
+		c.Defines[name] = value
+	}
+	return &c
+}
+
+// forPlatform sets the LLP64 data model for the 64-bit Windows,
+// unless the model is set explicitly.
+func (cfg *Config) forPlatform() *Config {
+	if cfg.OS != "windows" || len(cfg.Model) > 0 {
+		return cfg
+	}
+	arch, ok := arches[cfg.Arch]
+	if !ok {
+		arch = TargetArch(cfg.Arch)
+	}
+	if model, ok := models[arch]; (ok && model.Items[cc.Ptr].Size == 8) || len(cfg.Arch) == 0 {
+		cfg.Model = ModelLLP64
+	}
+	return cfg
+}
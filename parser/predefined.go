@@ -9,11 +9,26 @@ import (
 type TargetArch string
 
 const (
-	Arch32    TargetArch = "i386"
-	Arch48    TargetArch = "x86_48"
-	Arch64    TargetArch = "x86_64"
-	ArchArm32 TargetArch = "arm"
-	ArchArm64 TargetArch = "aarch64"
+	Arch32       TargetArch = "i386"
+	Arch48       TargetArch = "x86_48"
+	Arch64       TargetArch = "x86_64"
+	ArchLLP64    TargetArch = "x86_64_llp64"
+	ArchX32      TargetArch = "x32"
+	ArchArm32    TargetArch = "arm"
+	ArchArm32BE  TargetArch = "armbe"
+	ArchArm64    TargetArch = "aarch64"
+	ArchArm64BE  TargetArch = "aarch64_be"
+	ArchMips32   TargetArch = "mips"
+	ArchMips32LE TargetArch = "mipsel"
+	ArchMips64   TargetArch = "mips64"
+	ArchMips64LE TargetArch = "mips64el"
+	ArchPPC64    TargetArch = "ppc64"
+	ArchPPC64LE  TargetArch = "ppc64le"
+	ArchSparc32  TargetArch = "sparc"
+	ArchSparc64  TargetArch = "sparc64"
+	ArchRISCV64  TargetArch = "riscv64"
+	ArchS390x    TargetArch = "s390x"
+	ArchWasm32   TargetArch = "wasm32"
 )
 
 var builtinBase = `
@@ -54,6 +69,9 @@ var basePredefines = `
 #define __POSIX_C_DEPRECATED(ver)
 #define __has_include_next(...) 1
 
+#define __ORDER_LITTLE_ENDIAN__ 1234
+#define __ORDER_BIG_ENDIAN__ 4321
+
 #define __FLT_MIN__ 0
 #define __DBL_MIN__ 0
 #define __LDBL_MIN__ 0
@@ -61,29 +79,186 @@ var basePredefines = `
 void __GO__(char*, ...);
 `
 
+const (
+	littleEndian = `#define __BYTE_ORDER__ __ORDER_LITTLE_ENDIAN__`
+	bigEndian    = `#define __BYTE_ORDER__ __ORDER_BIG_ENDIAN__`
+	lp64         = "#define __LP64__ 1\n#define _LP64 1"
+	ilp32        = "#define __ILP32__ 1\n#define _ILP32 1"
+)
+
+// archPredefines follow https://sourceforge.net/p/predef/wiki/Architectures/
+// and the macros defined by GCC and Clang for the targets.
 var archPredefines = map[TargetArch]string{
-	Arch32: `#define __i386__ 1`,
-	Arch48: `#define __x86_64__ 1`,
-	Arch64: `#define __x86_64__ 1`,
+	Arch32: strings.Join([]string{
+		`#define __i386__ 1`,
+		ilp32, littleEndian,
+	}, "\n"),
+	Arch48: strings.Join([]string{
+		`#define __x86_64__ 1`,
+		littleEndian,
+	}, "\n"),
+	Arch64: strings.Join([]string{
+		`#define __x86_64__ 1`,
+		lp64, littleEndian,
+	}, "\n"),
+	ArchLLP64: strings.Join([]string{
+		`#define __x86_64__ 1`,
+		`#define _WIN32 1`,
+		`#define _WIN64 1`,
+		littleEndian,
+	}, "\n"),
+	ArchX32: strings.Join([]string{
+		`#define __x86_64__ 1`,
+		ilp32, littleEndian,
+	}, "\n"),
 	ArchArm32: strings.Join([]string{
 		`#define __ARM_EABI__ 1`,
 		`#define __arm__ 1`,
+		`#define __ARMEL__ 1`,
+		ilp32, littleEndian,
 	}, "\n"),
-	ArchArm64: strings.Join([]string{
+	ArchArm32BE: strings.Join([]string{
 		`#define __ARM_EABI__ 1`,
+		`#define __arm__ 1`,
+		`#define __ARMEB__ 1`,
+		ilp32, bigEndian,
+	}, "\n"),
+	ArchArm64: strings.Join([]string{
 		`#define __aarch64__ 1`,
+		lp64, littleEndian,
+	}, "\n"),
+	ArchArm64BE: strings.Join([]string{
+		`#define __aarch64__ 1`,
+		`#define __AARCH64EB__ 1`,
+		lp64, bigEndian,
+	}, "\n"),
+	ArchMips32: strings.Join([]string{
+		`#define __mips__ 1`,
+		`#define __MIPSEB__ 1`,
+		ilp32, bigEndian,
+	}, "\n"),
+	ArchMips32LE: strings.Join([]string{
+		`#define __mips__ 1`,
+		`#define __MIPSEL__ 1`,
+		ilp32, littleEndian,
+	}, "\n"),
+	ArchMips64: strings.Join([]string{
+		`#define __mips__ 1`,
+		`#define __mips64 1`,
+		`#define __MIPSEB__ 1`,
+		lp64, bigEndian,
+	}, "\n"),
+	ArchMips64LE: strings.Join([]string{
+		`#define __mips__ 1`,
+		`#define __mips64 1`,
+		`#define __MIPSEL__ 1`,
+		lp64, littleEndian,
+	}, "\n"),
+	ArchPPC64: strings.Join([]string{
+		`#define __powerpc__ 1`,
+		`#define __powerpc64__ 1`,
+		`#define __PPC64__ 1`,
+		lp64, bigEndian,
+	}, "\n"),
+	ArchPPC64LE: strings.Join([]string{
+		`#define __powerpc__ 1`,
+		`#define __powerpc64__ 1`,
+		`#define __PPC64__ 1`,
+		`#define _CALL_ELF 2`,
+		lp64, littleEndian,
+	}, "\n"),
+	ArchSparc32: strings.Join([]string{
+		`#define __sparc__ 1`,
+		ilp32, bigEndian,
+	}, "\n"),
+	ArchSparc64: strings.Join([]string{
+		`#define __sparc__ 1`,
+		`#define __sparc_v9__ 1`,
+		`#define __arch64__ 1`,
+		lp64, bigEndian,
+	}, "\n"),
+	ArchRISCV64: strings.Join([]string{
+		`#define __riscv 1`,
+		`#define __riscv_xlen 64`,
+		lp64, littleEndian,
+	}, "\n"),
+	ArchS390x: strings.Join([]string{
+		`#define __s390__ 1`,
+		`#define __s390x__ 1`,
+		lp64, bigEndian,
+	}, "\n"),
+	ArchWasm32: strings.Join([]string{
+		`#define __wasm__ 1`,
+		`#define __wasm32__ 1`,
+		ilp32, littleEndian,
 	}, "\n"),
-	// TODO(xlab): https://sourceforge.net/p/predef/wiki/Architectures/
 }
 
 var models = map[TargetArch]*cc.Model{
-	Arch32:    model32,
-	Arch48:    model48,
-	Arch64:    model64,
-	ArchArm32: model32,
-	ArchArm64: model64,
+	Arch32:       model32,
+	Arch48:       model48,
+	Arch64:       model64,
+	ArchLLP64:    modelLLP64,
+	ArchX32:      modelX32,
+	ArchArm32:    model32Aligned,
+	ArchArm32BE:  model32Aligned,
+	ArchArm64:    model64,
+	ArchArm64BE:  model64,
+	ArchMips32:   model32Aligned,
+	ArchMips32LE: model32Aligned,
+	ArchMips64:   model64,
+	ArchMips64LE: model64,
+	ArchPPC64:    model64,
+	ArchPPC64LE:  model64,
+	ArchSparc32:  model32Aligned,
+	ArchSparc64:  model64,
+	ArchRISCV64:  modelRISCV64,
+	ArchS390x:    modelS390x,
+	ArchWasm32:   modelWasm32,
+}
+
+// DataModel names the sizes of int, long and pointers, it overrides
+// the model of the target arch.
+type DataModel string
+
+const (
+	ModelILP32 DataModel = "ilp32"
+	ModelLP64  DataModel = "lp64"
+	ModelLLP64 DataModel = "llp64"
+)
+
+var dataModels = map[DataModel]*cc.Model{
+	ModelILP32: model32,
+	ModelLP64:  model64,
+	ModelLLP64: modelLLP64,
+}
+
+var dataModelPredefines = map[DataModel]string{
+	ModelILP32: ilp32,
+	ModelLP64:  lp64,
+}
+
+// archPredefinesFor returns the macros of the target arch, if the data model
+// is set explicitly the macros of the model replace the ones of the arch.
+func archPredefinesFor(cfg *Config) string {
+	defs := archPredefines[cfg.archBits]
+	if len(cfg.Model) == 0 {
+		return defs
+	}
+	var lines []string
+	for _, line := range strings.Split(defs, "\n") {
+		if !strings.Contains(lp64, line) && !strings.Contains(ilp32, line) {
+			lines = append(lines, line)
+		}
+	}
+	if modelDefs := dataModelPredefines[cfg.Model]; len(modelDefs) > 0 {
+		lines = append(lines, modelDefs)
+	}
+	return strings.Join(lines, "\n")
 }
 
+// arches maps GOARCH values and the common target names to the arches,
+// the TargetArch values themselves are accepted as well.
 var arches = map[string]TargetArch{
 	"386":         Arch32,
 	"arm":         ArchArm32,
@@ -92,23 +267,31 @@ var arches = map[string]TargetArch{
 	"armv8a":      ArchArm64,
 	"armeabi-v7a": ArchArm32,
 	"armeabi-v8a": ArchArm64,
-	"armbe":       ArchArm32,
-	"mips":        Arch32,
-	"mipsle":      Arch32,
-	"sparc":       Arch32,
+	"armbe":       ArchArm32BE,
+	"mips":        ArchMips32,
+	"mipsle":      ArchMips32LE,
+	"sparc":       ArchSparc32,
 	"amd64":       Arch64,
-	"amd64p32":    ArchArm32,
+	"amd64p32":    ArchX32,
 	"arm64":       ArchArm64,
-	"arm64be":     ArchArm64,
-	"ppc64":       Arch64,
-	"ppc64le":     Arch64,
-	"mips64":      Arch64,
-	"mips64le":    Arch64,
-	"mips64p32":   Arch48,
-	"mips64p32le": Arch48,
-	"sparc64":     Arch64,
+	"arm64be":     ArchArm64BE,
+	"ppc64":       ArchPPC64,
+	"ppc64le":     ArchPPC64LE,
+	"mips64":      ArchMips64,
+	"mips64le":    ArchMips64LE,
+	// n32 ABI shares the sizes with the 32-bit MIPS
+	"mips64p32":   ArchMips32,
+	"mips64p32le": ArchMips32LE,
+	"sparc64":     ArchSparc64,
+	"riscv64":     ArchRISCV64,
+	"s390x":       ArchS390x,
+	"wasm":        ArchWasm32,
+	"wasm32":      ArchWasm32,
 }
 
+// model32 is the i386 model, it aligns long longs and doubles to 4 bytes in structs
+// and has a 12-byte long double. The sizes of cc must be powers of two, so the long double
+// item carries the one of the ABI in More, the translator lays the structs out with it.
 var model32 = &cc.Model{
 	Items: map[cc.Kind]cc.ModelItem{
 		cc.Ptr:               {Size: 4, Align: 4, StructAlign: 4, More: "__TODO_PTR"},
@@ -123,11 +306,11 @@ var model32 = &cc.Model{
 		cc.UInt:              {Size: 4, Align: 4, StructAlign: 4, More: "uint32"},
 		cc.Long:              {Size: 4, Align: 4, StructAlign: 4, More: "int32"},
 		cc.ULong:             {Size: 4, Align: 4, StructAlign: 4, More: "uint32"},
-		cc.LongLong:          {Size: 8, Align: 8, StructAlign: 8, More: "int64"},
-		cc.ULongLong:         {Size: 8, Align: 8, StructAlign: 8, More: "uint64"},
+		cc.LongLong:          {Size: 8, Align: 8, StructAlign: 4, More: "int64"},
+		cc.ULongLong:         {Size: 8, Align: 8, StructAlign: 4, More: "uint64"},
 		cc.Float:             {Size: 4, Align: 4, StructAlign: 4, More: "float32"},
 		cc.Double:            {Size: 8, Align: 8, StructAlign: 4, More: "float64"},
-		cc.LongDouble:        {Size: 8, Align: 8, StructAlign: 4, More: "float64"},
+		cc.LongDouble:        {Size: 16, Align: 8, StructAlign: 4, More: cc.ModelItem{Size: 12, Align: 4, StructAlign: 4, More: "float64"}},
 		cc.Bool:              {Size: 1, Align: 1, StructAlign: 1, More: "bool"},
 		cc.FloatComplex:      {Size: 8, Align: 8, StructAlign: 8, More: "complex64"},
 		cc.DoubleComplex:     {Size: 16, Align: 16, StructAlign: 16, More: "complex128"},
@@ -152,8 +335,8 @@ var model48 = &cc.Model{
 		cc.LongLong:          {Size: 8, Align: 8, StructAlign: 8, More: "int64"},
 		cc.ULongLong:         {Size: 8, Align: 8, StructAlign: 8, More: "uint64"},
 		cc.Float:             {Size: 4, Align: 4, StructAlign: 4, More: "float32"},
-		cc.Double:            {Size: 8, Align: 8, StructAlign: 4, More: "float64"},
-		cc.LongDouble:        {Size: 8, Align: 8, StructAlign: 4, More: "float64"},
+		cc.Double:            {Size: 8, Align: 8, StructAlign: 8, More: "float64"},
+		cc.LongDouble:        {Size: 8, Align: 8, StructAlign: 8, More: "float64"},
 		cc.Bool:              {Size: 1, Align: 1, StructAlign: 1, More: "bool"},
 		cc.FloatComplex:      {Size: 8, Align: 8, StructAlign: 8, More: "complex64"},
 		cc.DoubleComplex:     {Size: 16, Align: 16, StructAlign: 16, More: "complex128"},
@@ -178,11 +361,83 @@ var model64 = &cc.Model{
 		cc.LongLong:          {Size: 8, Align: 8, StructAlign: 8, More: "int64"},
 		cc.ULongLong:         {Size: 8, Align: 8, StructAlign: 8, More: "uint64"},
 		cc.Float:             {Size: 4, Align: 4, StructAlign: 4, More: "float32"},
-		cc.Double:            {Size: 8, Align: 8, StructAlign: 4, More: "float64"},
-		cc.LongDouble:        {Size: 8, Align: 8, StructAlign: 4, More: "float64"},
+		cc.Double:            {Size: 8, Align: 8, StructAlign: 8, More: "float64"},
+		cc.LongDouble:        {Size: 16, Align: 16, StructAlign: 16, More: "float64"},
 		cc.Bool:              {Size: 1, Align: 1, StructAlign: 1, More: "bool"},
 		cc.FloatComplex:      {Size: 8, Align: 8, StructAlign: 8, More: "complex64"},
 		cc.DoubleComplex:     {Size: 16, Align: 16, StructAlign: 16, More: "complex128"},
 		cc.LongDoubleComplex: {Size: 16, Align: 16, StructAlign: 16, More: "complex128"},
 	},
 }
+
+// modelLLP64 is the Windows x64 model: long stays 4 bytes while pointers are 8.
+var modelLLP64 = &cc.Model{
+	Items: map[cc.Kind]cc.ModelItem{
+		cc.Ptr:               {Size: 8, Align: 8, StructAlign: 8, More: "__TODO_PTR"},
+		cc.UintPtr:           {Size: 8, Align: 8, StructAlign: 8, More: "uintptr"},
+		cc.Void:              {Size: 0, Align: 1, StructAlign: 1, More: "__TODO_VOID"},
+		cc.Char:              {Size: 1, Align: 1, StructAlign: 1, More: "int8"},
+		cc.SChar:             {Size: 1, Align: 1, StructAlign: 1, More: "int8"},
+		cc.UChar:             {Size: 1, Align: 1, StructAlign: 1, More: "byte"},
+		cc.Short:             {Size: 2, Align: 2, StructAlign: 2, More: "int16"},
+		cc.UShort:            {Size: 2, Align: 2, StructAlign: 2, More: "uint16"},
+		cc.Int:               {Size: 4, Align: 4, StructAlign: 4, More: "int32"},
+		cc.UInt:              {Size: 4, Align: 4, StructAlign: 4, More: "uint32"},
+		cc.Long:              {Size: 4, Align: 4, StructAlign: 4, More: "int32"},
+		cc.ULong:             {Size: 4, Align: 4, StructAlign: 4, More: "uint32"},
+		cc.LongLong:          {Size: 8, Align: 8, StructAlign: 8, More: "int64"},
+		cc.ULongLong:         {Size: 8, Align: 8, StructAlign: 8, More: "uint64"},
+		cc.Float:             {Size: 4, Align: 4, StructAlign: 4, More: "float32"},
+		cc.Double:            {Size: 8, Align: 8, StructAlign: 8, More: "float64"},
+		cc.LongDouble:        {Size: 8, Align: 8, StructAlign: 8, More: "float64"},
+		cc.Bool:              {Size: 1, Align: 1, StructAlign: 1, More: "bool"},
+		cc.FloatComplex:      {Size: 8, Align: 8, StructAlign: 8, More: "complex64"},
+		cc.DoubleComplex:     {Size: 16, Align: 16, StructAlign: 16, More: "complex128"},
+		cc.LongDoubleComplex: {Size: 16, Align: 16, StructAlign: 16, More: "complex128"},
+	},
+}
+
+// model32Aligned is the ILP32 model of the targets that align doubles and long longs
+// to 8 bytes in structs and have a long double of the double size.
+var model32Aligned = derivedModel(model32, map[cc.Kind]cc.ModelItem{
+	cc.LongLong:   {Size: 8, Align: 8, StructAlign: 8, More: "int64"},
+	cc.ULongLong:  {Size: 8, Align: 8, StructAlign: 8, More: "uint64"},
+	cc.Double:     {Size: 8, Align: 8, StructAlign: 8, More: "float64"},
+	cc.LongDouble: {Size: 8, Align: 8, StructAlign: 8, More: "float64"},
+})
+
+// modelX32 is the x32 ABI of x86_64, it keeps the 16-byte long double.
+var modelX32 = derivedModel(model32Aligned, map[cc.Kind]cc.ModelItem{
+	cc.LongDouble: {Size: 16, Align: 16, StructAlign: 16, More: "float64"},
+})
+
+var modelWasm32 = derivedModel(model32, map[cc.Kind]cc.ModelItem{
+	cc.LongLong:   {Size: 8, Align: 8, StructAlign: 8, More: "int64"},
+	cc.ULongLong:  {Size: 8, Align: 8, StructAlign: 8, More: "uint64"},
+	cc.Double:     {Size: 8, Align: 8, StructAlign: 8, More: "float64"},
+	cc.LongDouble: {Size: 16, Align: 16, StructAlign: 16, More: "float64"},
+})
+
+var modelRISCV64 = derivedModel(model64, map[cc.Kind]cc.ModelItem{
+	cc.Double:     {Size: 8, Align: 8, StructAlign: 8, More: "float64"},
+	cc.LongDouble: {Size: 16, Align: 16, StructAlign: 16, More: "float64"},
+})
+
+var modelS390x = derivedModel(model64, map[cc.Kind]cc.ModelItem{
+	cc.Double:     {Size: 8, Align: 8, StructAlign: 8, More: "float64"},
+	cc.LongDouble: {Size: 16, Align: 8, StructAlign: 8, More: "float64"},
+})
+
+// derivedModel returns a copy of the base model with the items replaced.
+func derivedModel(base *cc.Model, items map[cc.Kind]cc.ModelItem) *cc.Model {
+	model := &cc.Model{
+		Items: make(map[cc.Kind]cc.ModelItem, len(base.Items)),
+	}
+	for kind, item := range base.Items {
+		model.Items[kind] = item
+	}
+	for kind, item := range items {
+		model.Items[kind] = item
+	}
+	return model
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
@@ -27,25 +28,37 @@ const (
 	BufTypes
 	BufUnions
 	BufHelpers
+	BufLayoutTest
+	BufAllocsDebug
 	BufMain
 )
 
 var goBufferNames = map[Buf]string{
-	BufDoc:     "doc",
-	BufConst:   "const",
-	BufTypes:   "types",
-	BufUnions:  "unions",
-	BufHelpers: "cgo_helpers",
+	BufDoc:         "doc",
+	BufConst:       "const",
+	BufTypes:       "types",
+	BufUnions:      "unions",
+	BufHelpers:     "cgo_helpers",
+	BufLayoutTest:  "layout_test",
+	BufAllocsDebug: "cgo_helpers_debug",
 }
 
 type Process struct {
 	cfg          ProcessConfig
+	tr           *translator.Translator
 	gen          *generator.Generator
 	genSync      sync.WaitGroup
 	goBuffers    map[Buf]*bytes.Buffer
 	chHelpersBuf *bytes.Buffer
 	ccHelpersBuf *bytes.Buffer
 	outputPath   string
+	inputs       []string
+	outputs      []string
+
+	// targets are the processes of each arch or platform listed in the parser config,
+	// their outputs are merged into shared and target-specific files.
+	targets  []*Process
+	suffixes []string
 }
 
 type ProcessConfig struct {
@@ -54,14 +67,16 @@ type ProcessConfig struct {
 	Parser     *parser.Config     `yaml:"PARSER"`
 }
 
-func NewProcess(configPath, outputPath string) (*Process, error) {
+// loadProcessConfig reads the config and amends the parser config with the
+// include paths and options given outside of it. The raw config data is returned as well.
+func loadProcessConfig(configPath string) (ProcessConfig, []byte, error) {
+	var cfg ProcessConfig
 	cfgData, err := ioutil.ReadFile(configPath)
 	if err != nil {
-		return nil, err
+		return cfg, nil, err
 	}
-	var cfg ProcessConfig
 	if err := yaml.Unmarshal(cfgData, &cfg); err != nil {
-		return nil, err
+		return cfg, nil, err
 	}
 	if cfg.Generator != nil {
 		paths := includePathsFromPkgConfig(cfg.Generator.PkgConfigOpts)
@@ -72,12 +87,37 @@ func NewProcess(configPath, outputPath string) (*Process, error) {
 		cfg.Parser.CCIncl = *ccIncl
 		cfg.Parser.IncludePaths = append(cfg.Parser.IncludePaths, paths...)
 		cfg.Parser.IncludePaths = append(cfg.Parser.IncludePaths, filepath.Dir(configPath))
+		for i := range cfg.Parser.Platforms {
+			platform := &cfg.Parser.Platforms[i]
+			for j, path := range platform.IncludePaths {
+				if !filepath.IsAbs(path) {
+					platform.IncludePaths[j] = filepath.Join(filepath.Dir(configPath), path)
+				}
+			}
+		}
 	} else {
-		return nil, errors.New("process: generator config was not specified")
+		return cfg, nil, errors.New("process: generator config was not specified")
 	}
+	return cfg, cfgData, nil
+}
 
+func NewProcess(configPath, outputPath string) (*Process, error) {
+	cfg, _, err := loadProcessConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := parser.Targets(cfg.Parser)
+	if err != nil {
+		return nil, err
+	} else if len(targets) > 0 {
+		return newMultiTargetProcess(cfg, targets, outputPath)
+	}
+	return newProcess(cfg, outputPath)
+}
+
+func newProcess(cfg ProcessConfig, outputPath string) (*Process, error) {
 	// parse the headers
-	unit, err := parser.ParseWith(cfg.Parser)
+	unit, inputs, err := parser.ParseWithSources(cfg.Parser)
 	if err != nil {
 		return nil, err
 	}
@@ -96,35 +136,56 @@ func NewProcess(configPath, outputPath string) (*Process, error) {
 	// begin generation
 	pkg := filepath.Base(cfg.Generator.PackageName)
 	gen, err := generator.New(pkg, cfg.Generator, tl)
-	gen.SetMaxMemory(generator.NewMemSpec(*maxMem))
 	if err != nil {
 		return nil, err
 	}
+	gen.SetMaxMemory(generator.NewMemSpec(*maxMem))
 	if *nostamp {
 		gen.DisableTimestamps()
 	}
 	c := &Process{
 		cfg:          cfg,
+		tr:           tl,
 		gen:          gen,
 		goBuffers:    make(map[Buf]*bytes.Buffer),
 		chHelpersBuf: new(bytes.Buffer),
 		ccHelpersBuf: new(bytes.Buffer),
 		outputPath:   outputPath,
+		inputs:       inputs,
 	}
 	c.goBuffers[BufMain] = new(bytes.Buffer)
 	for opt := range goBufferNames {
 		c.goBuffers[opt] = new(bytes.Buffer)
 	}
 	goHelpersBuf := c.goBuffers[BufHelpers]
+	c.genSync.Add(1)
 	go func() {
-		c.genSync.Add(1)
 		c.gen.MonitorAndWriteHelpers(goHelpersBuf, c.chHelpersBuf, c.ccHelpersBuf)
 		c.genSync.Done()
 	}()
 	return c, nil
 }
 
+// DisableTimestamps disables the timestamps in the headers of generated files.
+func (c *Process) DisableTimestamps() {
+	for _, proc := range c.targets {
+		proc.DisableTimestamps()
+	}
+	if c.gen != nil {
+		c.gen.DisableTimestamps()
+	}
+}
+
 func (c *Process) Generate(noCGO bool) {
+	if len(c.targets) > 0 {
+		for _, proc := range c.targets {
+			proc.Generate(noCGO)
+		}
+		return
+	}
+	if noCGO {
+		c.gen.DisableCGO()
+	}
 	main := c.goBuffers[BufMain]
 	if wr, ok := c.goBuffers[BufDoc]; ok {
 		if !c.gen.WriteDoc(wr) {
@@ -159,6 +220,12 @@ func (c *Process) Generate(noCGO bool) {
 	} else {
 		c.gen.WriteTypedefs(main)
 	}
+	if wr, ok := c.goBuffers[BufLayoutTest]; ok {
+		c.gen.WritePackageHeader(wr)
+		if n := c.gen.WriteLayoutTests(wr); n == 0 {
+			c.goBuffers[BufLayoutTest] = nil
+		}
+	}
 	if !noCGO {
 		if wr, ok := c.goBuffers[BufUnions]; ok {
 			c.gen.WritePackageHeader(wr)
@@ -170,100 +237,149 @@ func (c *Process) Generate(noCGO bool) {
 			c.gen.WriteUnions(main)
 		}
 		c.gen.WriteDeclares(main)
+		c.gen.WriteMacros(main)
+		if n := c.gen.WriteAllocsDebug(c.goBuffers[BufAllocsDebug]); n == 0 {
+			c.goBuffers[BufAllocsDebug] = nil
+		}
+		return
+	}
+	// without cgo the functions are called through the library loaded at runtime
+	n := c.gen.WriteDynamicDeclares(main)
+	if n += c.gen.WriteMacros(main); n == 0 {
+		c.goBuffers[BufMain] = nil
 	}
+	c.goBuffers[BufAllocsDebug] = nil
 }
 
 func (c *Process) Flush(noCGO bool) error {
-	c.gen.Close()
-	c.genSync.Wait()
+	var files map[string][]byte
+	if len(c.targets) > 0 {
+		targetFiles := make([]map[string][]byte, 0, len(c.targets))
+		for _, proc := range c.targets {
+			targetFiles = append(targetFiles, proc.render(noCGO))
+		}
+		merged, err := c.mergeTargetFiles(targetFiles)
+		if err != nil {
+			return err
+		}
+		files = merged
+	} else {
+		files = c.render(noCGO)
+	}
 	filePrefix := filepath.Join(c.outputPath, c.cfg.Generator.PackageName)
 	if err := os.MkdirAll(filePrefix, 0755); err != nil {
 		return err
 	}
-	createCHFile := func(name string) (*os.File, error) {
-		return os.Create(filepath.Join(filePrefix, fmt.Sprintf("%s.h", name)))
-	}
-	createCCFile := func(name string) (*os.File, error) {
-		return os.Create(filepath.Join(filePrefix, fmt.Sprintf("%s.c", name)))
-	}
-	createGoFile := func(name string) (*os.File, error) {
-		return os.Create(filepath.Join(filePrefix, fmt.Sprintf("%s.go", name)))
-	}
-	writeGoFile := func(opt Buf, name string) error {
-		if buf := c.goBuffers[opt]; buf != nil && buf.Len() > 0 {
-			if f, err := createGoFile(name); err == nil {
-				if err := flushBufferToFile(buf.Bytes(), f, true); err != nil {
-					f.Close()
-					return err
-				}
-				f.Close()
-			} else {
-				return err
-			}
-		}
-		return nil
-	}
-	writeCHFile := func(buf *bytes.Buffer, name string) error {
-		if f, err := createCHFile(name); err != nil {
-			return err
-		} else if err := flushBufferToFile(buf.Bytes(), f, false); err != nil {
-			f.Close()
-			return err
-		} else {
-			return f.Close()
-		}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
 	}
-	writeCCFile := func(buf *bytes.Buffer, name string) error {
-		if f, err := createCCFile(name); err != nil {
-			return err
-		} else if err := flushBufferToFile(buf.Bytes(), f, false); err != nil {
-			f.Close()
+	sort.Strings(names)
+	c.outputs = c.outputs[:0]
+	for _, name := range names {
+		path := filepath.Join(filePrefix, name)
+		c.outputs = append(c.outputs, path)
+		if err := writeFileIfChanged(path, files[name]); err != nil {
 			return err
-		} else {
-			return f.Close()
 		}
 	}
+	return nil
+}
 
-	if !noCGO {
-		pkg := filepath.Base(c.cfg.Generator.PackageName)
-		if err := writeGoFile(BufMain, pkg); err != nil {
-			return err
+// render finishes the generation and returns the contents of the output files by their names.
+func (c *Process) render(noCGO bool) map[string][]byte {
+	c.gen.Close()
+	c.genSync.Wait()
+	filePrefix := filepath.Join(c.outputPath, c.cfg.Generator.PackageName)
+	files := make(map[string][]byte)
+	addGoFile := func(opt Buf, name string) {
+		if buf := c.goBuffers[opt]; buf != nil && buf.Len() > 0 {
+			name = fmt.Sprintf("%s.go", name)
+			files[name] = c.formatSource(filepath.Join(filePrefix, name), buf.Bytes())
 		}
 	}
+
+	pkg := filepath.Base(c.cfg.Generator.PackageName)
+	addGoFile(BufMain, pkg)
 	for opt, name := range goBufferNames {
-		if err := writeGoFile(opt, name); err != nil {
-			return err
-		}
+		addGoFile(opt, name)
 	}
 	if noCGO {
-		return nil
+		return files
 	}
 	if c.chHelpersBuf.Len() > 0 {
-		if err := writeCHFile(c.chHelpersBuf, "cgo_helpers"); err != nil {
-			return err
-		}
+		files["cgo_helpers.h"] = c.chHelpersBuf.Bytes()
 	}
 	if c.ccHelpersBuf.Len() > 0 {
-		if err := writeCCFile(c.ccHelpersBuf, "cgo_helpers"); err != nil {
-			return err
-		}
+		files["cgo_helpers.c"] = c.ccHelpersBuf.Bytes()
 	}
-	return nil
+	return files
 }
 
-func flushBufferToFile(buf []byte, f *os.File, fmt bool) error {
-	if fmt {
-		if fmtBuf, err := imports.Process(f.Name(), buf, nil); err == nil {
-			_, err = f.Write(fmtBuf)
-			return err
-		} else {
-			log.Printf("[WARN] cannot gofmt %s: %s\n", f.Name(), err.Error())
-			f.Write(buf)
-			return nil
+// Inputs returns the paths of the files that have been parsed.
+func (c *Process) Inputs() []string {
+	return c.inputs
+}
+
+// Outputs returns the paths of the files produced by the last Flush,
+// including the ones that have been left intact.
+func (c *Process) Outputs() []string {
+	return c.outputs
+}
+
+func (c *Process) formatSource(name string, buf []byte) []byte {
+	fmtBuf, err := imports.Process(name, buf, nil)
+	if err != nil {
+		c.tr.Diagnostics().Report(translator.Diagnostic{
+			Kind:   translator.DiagDegraded,
+			Name:   filepath.Base(name),
+			File:   name,
+			Reason: fmt.Sprintf("cannot gofmt, the file is written as is: %v", err),
+		})
+		return buf
+	}
+	return fmtBuf
+}
+
+// Diagnostics returns the skipped and degraded declarations of the run. The
+// declarations filtered by the rules are only reported for the source files,
+// not for every header those have included.
+func (c *Process) Diagnostics() []translator.Diagnostic {
+	if len(c.targets) > 0 {
+		diags := translator.NewDiagnostics()
+		for _, proc := range c.targets {
+			for _, diag := range proc.Diagnostics() {
+				diags.Report(diag)
+			}
+		}
+		return diags.List()
+	}
+	sources := make(map[string]bool, len(c.cfg.Parser.SourcesPaths))
+	for _, path := range c.cfg.Parser.SourcesPaths {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
 		}
+		sources[path] = true
+	}
+	var list []translator.Diagnostic
+	for _, diag := range c.tr.Diagnostics().List() {
+		if diag.Kind == translator.DiagFiltered {
+			if abs, err := filepath.Abs(diag.File); err != nil || !sources[abs] {
+				continue
+			}
+		}
+		list = append(list, diag)
+	}
+	return list
+}
+
+// writeFileIfChanged writes the data unless the file already has the same contents,
+// so the build caches and file watchers are not disturbed by a no-op regeneration.
+func writeFileIfChanged(path string, data []byte) error {
+	if prev, err := ioutil.ReadFile(path); err == nil && bytes.Equal(prev, data) {
+		return nil
 	}
-	_, err := f.Write(buf)
-	return err
+	return ioutil.WriteFile(path, data, 0644)
 }
 
 func includePathsFromPkgConfig(opts []string) []string {
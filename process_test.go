@@ -0,0 +1,592 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xlab/c-for-go/parser"
+	"github.com/xlab/c-for-go/translator"
+)
+
+var update = flag.Bool("update", false, "Rewrite the golden files in testdata with the current output.")
+
+// TestGolden runs the whole parser → translator → generator pipeline for
+// each fixture in testdata and compares the output with the golden files.
+//
+// A fixture is a directory holding a c-for-go.yml config along with the
+// headers it references, the expected output lives in its golden subdirectory.
+// The output of the fixtures having a golden-nocgo subdirectory is also checked
+// in the pure Go mode.
+func TestGolden(t *testing.T) {
+	fixtures, err := ioutil.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range fixtures {
+		if !info.IsDir() {
+			continue
+		}
+		dir := filepath.Join("testdata", info.Name())
+		cfgPath, ok := configFromDir(dir)
+		if !ok {
+			continue
+		}
+		t.Run(info.Name(), func(t *testing.T) {
+			testGolden(t, cfgPath, filepath.Join(dir, "golden"), false)
+		})
+		noCGODir := filepath.Join(dir, "golden-nocgo")
+		if _, err := os.Stat(noCGODir); err != nil {
+			continue
+		}
+		t.Run(info.Name()+"-nocgo", func(t *testing.T) {
+			testGolden(t, cfgPath, noCGODir, true)
+		})
+	}
+}
+
+func testGolden(t *testing.T, cfgPath, goldenDir string, noCGO bool) {
+	outputPath, err := ioutil.TempDir("", "c-for-go-golden")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outputPath)
+
+	process := runProcess(t, cfgPath, outputPath, noCGO)
+	got, err := readFiles(filepath.Join(outputPath, process.cfg.Generator.PackageName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *update {
+		if err := os.RemoveAll(goldenDir); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(goldenDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for name, data := range got {
+			if err := ioutil.WriteFile(filepath.Join(goldenDir, name), data, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return
+	}
+	want, err := readFiles(goldenDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range sortedNames(want) {
+		if _, ok := got[name]; !ok {
+			t.Errorf("%s: file has not been generated", name)
+		}
+	}
+	for _, name := range sortedNames(got) {
+		wantData, ok := want[name]
+		if !ok {
+			t.Errorf("%s: unexpected file has been generated", name)
+			continue
+		}
+		if diff := diffLines(wantData, got[name]); len(diff) > 0 {
+			t.Errorf("%s: output differs from the golden file (run with -update to accept):\n%s", name, diff)
+		}
+	}
+}
+
+// TestVerify checks that the bindings generated for the fixtures compile and
+// link against a stub library, it requires a C compiler.
+func TestVerify(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping verification in short mode")
+	}
+	if _, err := exec.LookPath("cc"); err != nil {
+		t.Skip("skipping verification: no C compiler found")
+	}
+	for _, name := range []string{"basic", "mirror", "ownership", "finalizers", "allochooks", "arena", "callbacks", "dynamic"} {
+		outputPath, err := ioutil.TempDir("", "c-for-go-verify")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(outputPath)
+
+		process := runProcess(t, filepath.Join("testdata", name, "c-for-go.yml"), outputPath, false)
+		if err := process.Verify(true); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	// the failures are expected to point at the C declaration of the broken code
+	header, err := ioutil.ReadFile(filepath.Join("testdata", "basic", "foo.h"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	originOf := func(cName string) string {
+		for i, line := range strings.Split(string(header), "\n") {
+			if strings.Contains(line, cName+"(") {
+				return fmt.Sprintf("[%s as declared in basic/foo.h:%d]", cName, i+1)
+			}
+		}
+		return ""
+	}
+	breakages := map[string]struct {
+		from, to, origin string
+	}{
+		"type": {"__v := (int64)(__ret)", "__v := (string)(__ret)", originOf("foo_seek")},
+		"cgo":  {"C.foo_seek(cOffset)", "C.foo_seek_missing(cOffset)", originOf("foo_seek")},
+		// bar_internal is filtered out, so the stub library lacks it
+		"link": {"C.foo_seek(cOffset)", "C.long(C.bar_internal()) + C.long(cOffset*0)", originOf("bar_internal")},
+	}
+	for kind, edit := range breakages {
+		outputPath, err := ioutil.TempDir("", "c-for-go-verify")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(outputPath)
+
+		process := runProcess(t, filepath.Join("testdata", "basic", "c-for-go.yml"), outputPath, false)
+		path := filepath.Join(outputPath, process.cfg.Generator.PackageName, "foo.go")
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Contains(data, []byte(edit.from)) {
+			t.Fatalf("%s: %q is not found in the generated code", kind, edit.from)
+		}
+		writeFile(t, path, strings.Replace(string(data), edit.from, edit.to, 1))
+		err = process.Verify(true)
+		if err == nil {
+			t.Errorf("%s: broken bindings have been verified", kind)
+			continue
+		}
+		if !strings.Contains(err.Error(), edit.origin) {
+			t.Errorf("%s: the failure is not linked to %s:\n%v", kind, edit.origin, err)
+		}
+	}
+}
+
+// TestRuntime builds the bindings of the fixtures having a runtime subdirectory
+// and runs the tests found there against the C implementation it holds, so the
+// generated code is exercised beyond compiling. It requires a C compiler.
+// The tests in a runtime-nocgo subdirectory run against the bindings generated
+// without cgo, those don't need a C compiler.
+func TestRuntime(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping runtime tests in short mode")
+	}
+	_, err := exec.LookPath("cc")
+	hasCC := err == nil
+	fixtures, err := ioutil.ReadDir("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range fixtures {
+		dir := filepath.Join("testdata", info.Name())
+		if _, err := os.Stat(filepath.Join(dir, "runtime")); err == nil {
+			t.Run(info.Name(), func(t *testing.T) {
+				if !hasCC {
+					t.Skip("skipping runtime tests: no C compiler found")
+				}
+				testRuntime(t, dir, false)
+			})
+		}
+		if _, err := os.Stat(filepath.Join(dir, "runtime-nocgo")); err == nil {
+			t.Run(info.Name()+"-nocgo", func(t *testing.T) {
+				testRuntime(t, dir, true)
+			})
+		}
+	}
+}
+
+func testRuntime(t *testing.T, dir string, noCGO bool) {
+	outputPath, err := ioutil.TempDir("", "c-for-go-runtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(outputPath)
+
+	process := runProcess(t, filepath.Join(dir, "c-for-go.yml"), outputPath, noCGO)
+	pkgDir := filepath.Join(outputPath, process.cfg.Generator.PackageName)
+	runtimeDir, cgoEnabled := "runtime", "CGO_ENABLED=1"
+	if noCGO {
+		runtimeDir, cgoEnabled = "runtime-nocgo", "CGO_ENABLED=0"
+	}
+	files, err := readFiles(filepath.Join(dir, runtimeDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(pkgDir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile(t, filepath.Join(outputPath, "go.mod"), "module c_for_go_runtime\n\ngo 1.14\n")
+	includePath, err := filepath.Abs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd := exec.Command("go", "test", "-count=1", "-tags", "cforgo_debug", "./...")
+	cmd.Dir = outputPath
+	cmd.Env = append(os.Environ(), cgoEnabled, "GOFLAGS=-mod=mod",
+		"CGO_CFLAGS="+strings.Join(append(strings.Fields(os.Getenv("CGO_CFLAGS")), "-I"+includePath), " "))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("%v:\n%s", err, out)
+	}
+}
+
+// TestDiagnostics checks that the declarations missing from the bindings
+// of the fixtures are accounted for.
+func TestDiagnostics(t *testing.T) {
+	tests := map[string]map[string]translator.DiagnosticKind{
+		"basic": {
+			"FOO_LIMIT":    translator.DiagDegraded,
+			"FOO_CLAMP":    translator.DiagSkipped,
+			"bar_internal": translator.DiagFiltered,
+		},
+		"ownership": {
+			"buf_destroy": translator.DiagSkipped,
+		},
+	}
+	for _, name := range []string{"basic", "ownership"} {
+		outputPath, err := ioutil.TempDir("", "c-for-go-diag")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(outputPath)
+
+		process := runProcess(t, filepath.Join("testdata", name, "c-for-go.yml"), outputPath, false)
+		want := tests[name]
+		for _, diag := range process.Diagnostics() {
+			if kind, ok := want[diag.Name]; ok && kind == diag.Kind {
+				delete(want, diag.Name)
+			}
+		}
+		for declName, kind := range want {
+			t.Errorf("%s: %s: expected a %s diagnostic", name, declName, kind)
+		}
+	}
+}
+
+// TestDataModels checks that the width of long follows the data model
+// of the target arch or the model set explicitly, and so do the struct layouts.
+func TestDataModels(t *testing.T) {
+	tests := []struct {
+		arch  string
+		model parser.DataModel
+		want  string
+	}{
+		{"", "", "int64"},
+		{"386", "", "int32"},
+		{"arm", "", "int32"},
+		{"amd64", parser.ModelLLP64, "int32"},
+		{"386", parser.ModelLP64, "int64"},
+		{"x86_64_llp64", "", "int32"},
+		{"amd64p32", "", "int32"},
+		{"mips", "", "int32"},
+		{"riscv64", "", "int64"},
+		{"s390x", "", "int64"},
+		{"wasm32", "", "int32"},
+	}
+	for _, tt := range tests {
+		unit, err := parser.ParseWith(&parser.Config{
+			Arch:         tt.arch,
+			Model:        tt.model,
+			SourcesPaths: []string{filepath.Join("testdata", "basic", "foo.h")},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tr, err := translator.New(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tr.Learn(unit)
+		var got string
+		for _, decl := range tr.Declares() {
+			if decl.Name == "foo_seek" {
+				got = tr.TranslateSpec(decl.Spec.(*translator.CFunctionSpec).Return).String()
+			}
+		}
+		if got != tt.want {
+			t.Errorf("arch %q model %q: long is translated as %q, want %q", tt.arch, tt.model, got, tt.want)
+		}
+	}
+
+	// the layout of long long and long double in structs follows the arch as well
+	dir, err := ioutil.TempDir("", "c-for-go-models")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	headerPath := filepath.Join(dir, "models.h")
+	writeFile(t, headerPath, "typedef struct LL { char c; long long v; } LL;\n"+
+		"typedef struct LD { char c; long double v; } LD;\n")
+	layouts := []struct {
+		arch           string
+		llOffset, llSz int
+		ldOffset, ldSz int
+	}{
+		{"386", 4, 12, 4, 16},
+		{"amd64", 8, 16, 16, 32},
+		{"arm64", 8, 16, 16, 32},
+		{"ppc64le", 8, 16, 16, 32},
+		{"amd64p32", 8, 16, 16, 32},
+		{"arm", 8, 16, 8, 16},
+	}
+	for _, tt := range layouts {
+		unit, err := parser.ParseWith(&parser.Config{
+			Arch:         tt.arch,
+			SourcesPaths: []string{headerPath},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tr, err := translator.New(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tr.Learn(unit)
+		want := map[string][2]int{
+			"LL": {tt.llOffset, tt.llSz},
+			"LD": {tt.ldOffset, tt.ldSz},
+		}
+		for _, decl := range tr.Typedefs() {
+			spec, ok := decl.Spec.(*translator.CStructSpec)
+			if !ok || len(spec.Members) != 2 {
+				continue
+			}
+			got := [2]int{spec.Members[1].Offset, spec.Size}
+			if got != want[decl.Name] {
+				t.Errorf("arch %q: %s has v at %d and size %d, want %d and %d",
+					tt.arch, decl.Name, got[0], got[1], want[decl.Name][0], want[decl.Name][1])
+			}
+			delete(want, decl.Name)
+		}
+		for name := range want {
+			t.Errorf("arch %q: %s has not been translated", tt.arch, name)
+		}
+	}
+}
+
+// TestConcurrentProcesses checks that the processes running in parallel
+// produce the same output as a single one.
+func TestConcurrentProcesses(t *testing.T) {
+	const n = 4
+	cfgPath := filepath.Join("testdata", "basic", "c-for-go.yml")
+	outputs := make([]map[string][]byte, n)
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			outputPath, err := ioutil.TempDir("", "c-for-go-concurrent")
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer os.RemoveAll(outputPath)
+			process, err := NewProcess(cfgPath, outputPath)
+			if err != nil {
+				errs <- err
+				return
+			}
+			process.DisableTimestamps()
+			process.Generate(false)
+			if err := process.Flush(false); err != nil {
+				errs <- err
+				return
+			}
+			outputs[i], err = readFiles(filepath.Join(outputPath, process.cfg.Generator.PackageName))
+			errs <- err
+		}(i)
+	}
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 1; i < n; i++ {
+		for _, name := range sortedNames(outputs[0]) {
+			if diff := diffLines(outputs[0][name], outputs[i][name]); len(diff) > 0 {
+				t.Errorf("%s: output of process %d differs:\n%s", name, i, diff)
+			}
+		}
+	}
+}
+
+// TestRunCache checks that a run with the same inputs is skipped with the diagnostics
+// of the previous run, that a changed header makes the next run regenerate the package
+// without rewriting the files that stay the same, and that the stale outputs are removed.
+func TestRunCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "c-for-go-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	defer setEnv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))()
+	defer setEnv("HOME", filepath.Join(dir, "home"))()
+	prevOutput, prevNostamp := *outputPath, *nostamp
+	defer func() {
+		*outputPath, *nostamp = prevOutput, prevNostamp
+	}()
+	*outputPath, *nostamp = filepath.Join(dir, "out"), true
+
+	cfgPath := filepath.Join(dir, "cached.yml")
+	headerPath := filepath.Join(dir, "cached.h")
+	writeFile(t, cfgPath, `---
+GENERATOR:
+  PackageName: cached
+  Includes: ["cached.h"]
+PARSER:
+  SourcesPaths: ["cached.h"]
+TRANSLATOR:
+  Rules:
+    global:
+      - {action: accept, from: "^cached_"}
+      - {transform: export}
+`)
+	header := `typedef struct cached_pair {
+	int a;
+	int b;
+} cached_pair;
+
+int cached_sum(cached_pair *p);
+int internal_sum(cached_pair *p);
+`
+	writeFile(t, headerPath, header)
+	claimOutput := func(cfgPath, dir string) error { return nil }
+	run := func(want string) []translator.Diagnostic {
+		t.Helper()
+		state, diags, err := runConfig(cfgPath, claimOutput)
+		if err != nil {
+			t.Fatal(err)
+		} else if !strings.HasPrefix(state, want) {
+			t.Fatalf("the run is %q, want %q", state, want)
+		}
+		return diags
+	}
+	typesPath := filepath.Join(*outputPath, "cached", "types.go")
+
+	diags := run(stateDone)
+	if replayed := run(stateUpToDate); fmt.Sprint(replayed) != fmt.Sprint(diags) || len(diags) == 0 {
+		t.Errorf("the diagnostics replayed are %v, want %v", replayed, diags)
+	}
+
+	past := time.Now().Add(-time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(typesPath, past, past); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, headerPath, header+"\n// a comment that changes no output\n")
+	run(stateDone)
+	if info, err := os.Stat(typesPath); err != nil {
+		t.Fatal(err)
+	} else if !info.ModTime().Equal(past) {
+		t.Errorf("types.go has been rewritten with the same contents")
+	}
+
+	writeFile(t, headerPath, "int cached_answer(void);\n")
+	run(stateDone)
+	if _, err := os.Stat(typesPath); !os.IsNotExist(err) {
+		t.Errorf("types.go is not produced anymore, but it has not been removed")
+	}
+	run(stateUpToDate)
+}
+
+func setEnv(key, value string) (restore func()) {
+	prev, ok := os.LookupEnv(key)
+	os.Setenv(key, value)
+	return func() {
+		if ok {
+			os.Setenv(key, prev)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func runProcess(t *testing.T, cfgPath, outputPath string, noCGO bool) *Process {
+	process, err := NewProcess(cfgPath, outputPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	process.DisableTimestamps()
+	process.Generate(noCGO)
+	if err := process.Flush(noCGO); err != nil {
+		t.Fatal(err)
+	}
+	return process
+}
+
+func readFiles(dir string) (map[string][]byte, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]byte, len(infos))
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(dir, info.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files[info.Name()] = data
+	}
+	return files, nil
+}
+
+func sortedNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// diffLines reports the lines that differ between want and got, it's not a
+// minimal diff but it's enough to locate the regression.
+func diffLines(want, got []byte) string {
+	if bytes.Equal(want, got) {
+		return ""
+	}
+	wantLines := strings.Split(string(want), "\n")
+	gotLines := strings.Split(string(got), "\n")
+	n := len(wantLines)
+	if len(gotLines) > n {
+		n = len(gotLines)
+	}
+	const maxReported = 10
+	buf := new(bytes.Buffer)
+	var reported int
+	for i := 0; i < n && reported < maxReported; i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			continue
+		}
+		fmt.Fprintf(buf, "line %d:\n\t-%s\n\t+%s\n", i+1, w, g)
+		reported++
+	}
+	if reported == maxReported {
+		buf.WriteString("\t...\n")
+	}
+	return buf.String()
+}
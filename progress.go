@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tj/go-spin"
+)
+
+const (
+	stateQueued   = ""
+	stateRunning  = "running"
+	stateDone     = "done."
+	stateUpToDate = "up to date."
+	stateFailed   = "failed."
+)
+
+// progress renders a progress line for each of the package configs being processed,
+// the lines are redrawn in place so the concurrent runs don't mix their output.
+type progress struct {
+	mux    sync.Mutex
+	fancy  bool
+	paths  []string
+	states []string
+	drawn  int
+	spin   *spin.Spinner
+}
+
+func newProgress(paths []string, fancy bool) *progress {
+	return &progress{
+		fancy:  fancy,
+		paths:  paths,
+		states: make([]string, len(paths)),
+		spin:   spin.New(),
+	}
+}
+
+// Set updates the state of the i-th config.
+func (p *progress) Set(i int, state string) {
+	p.mux.Lock()
+	p.states[i] = state
+	p.mux.Unlock()
+}
+
+// Start starts redrawing the progress lines, the returned func stops it
+// and draws the final states.
+func (p *progress) Start() (stop func()) {
+	if !p.fancy {
+		return func() {}
+	}
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		t := time.NewTicker(100 * time.Millisecond)
+		defer t.Stop()
+		for {
+			p.draw()
+			select {
+			case <-done:
+				p.draw()
+				return
+			case <-t.C:
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+func (p *progress) draw() {
+	p.mux.Lock()
+	defer p.mux.Unlock()
+	buf := new(bytes.Buffer)
+	if p.drawn > 0 {
+		fmt.Fprintf(buf, "\033[%dA", p.drawn)
+	}
+	frame := p.spin.Next()
+	for i, path := range p.paths {
+		state := p.states[i]
+		if state == stateRunning {
+			state = frame
+		}
+		fmt.Fprintf(buf, "\r  \033[36mprocessing %s\033[m %s\033[K\n", path, state)
+	}
+	p.drawn = len(p.paths)
+	os.Stdout.Write(buf.Bytes())
+}
@@ -0,0 +1,423 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build/constraint"
+	goparser "go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/xlab/c-for-go/parser"
+)
+
+// knownGOARCH lists the GOARCH values the parser has the targets for,
+// those are also recognized by the go tool in the file name suffixes.
+var knownGOARCH = map[string]bool{
+	"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true,
+	"arm64": true, "arm64be": true, "mips": true, "mipsle": true, "mips64": true,
+	"mips64le": true, "mips64p32": true, "mips64p32le": true, "ppc64": true,
+	"ppc64le": true, "riscv64": true, "s390x": true, "sparc": true, "sparc64": true,
+	"wasm": true,
+}
+
+// newMultiTargetProcess creates a process per arch or platform listed in the parser config.
+// The process of the host platform and arch, or the first one, is used to verify the bindings.
+func newMultiTargetProcess(cfg ProcessConfig, targets []parser.Target, outputPath string) (*Process, error) {
+	c := &Process{
+		outputPath: outputPath,
+	}
+	var host *Process
+	for _, target := range targets {
+		if len(target.GOARCH) > 0 && !knownGOARCH[target.GOARCH] {
+			return nil, fmt.Errorf("process: %s is not a GOARCH value", target.GOARCH)
+		}
+		targetCfg := cfg
+		targetCfg.Parser = target.Config
+		proc, err := newProcess(targetCfg, outputPath)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", target.Suffix(), err)
+		}
+		c.targets = append(c.targets, proc)
+		c.suffixes = append(c.suffixes, target.Suffix())
+		if (target.GOOS == "" || target.GOOS == runtime.GOOS) &&
+			(target.GOARCH == "" || target.GOARCH == runtime.GOARCH) && host == nil {
+			host = proc
+		}
+	}
+	if host == nil {
+		host = c.targets[0]
+	}
+	c.cfg = host.cfg
+	c.tr = host.tr
+
+	inputs := make(map[string]bool)
+	for _, proc := range c.targets {
+		for _, path := range proc.inputs {
+			if !inputs[path] {
+				inputs[path] = true
+				c.inputs = append(c.inputs, path)
+			}
+		}
+	}
+	sort.Strings(c.inputs)
+	return c, nil
+}
+
+// mergeTargetFiles merges the files generated for each target. The files that are the same
+// for all the targets are written once, the Go files that differ are split by the top-level
+// declarations: the ones that are the same stay in the shared file, the others go into
+// the files with GOOS or GOARCH suffix, e.g. types_arm.go or types_windows.go.
+func (c *Process) mergeTargetFiles(targetFiles []map[string][]byte) (map[string][]byte, error) {
+	var names []string
+	seen := make(map[string]bool)
+	for _, files := range targetFiles {
+		for name := range files {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	merged := make(map[string][]byte)
+	var split bool
+	for _, name := range names {
+		contents := make([][]byte, len(targetFiles))
+		same := true
+		for i, files := range targetFiles {
+			contents[i] = files[name]
+			if contents[i] == nil || !bytes.Equal(contents[i], contents[0]) {
+				same = false
+			}
+		}
+		if same {
+			merged[name] = contents[0]
+			continue
+		}
+		split = true
+		ext := filepath.Ext(name)
+		switch ext {
+		case ".go":
+			if err := c.splitGoFile(merged, name, contents); err != nil {
+				return nil, err
+			}
+		case ".c":
+			for i, data := range contents {
+				if data != nil {
+					merged[targetFileName(name, c.suffixes[i])] = data
+				}
+			}
+		default:
+			return nil, fmt.Errorf("process: %s differs between the targets %s",
+				name, strings.Join(c.suffixes, ", "))
+		}
+	}
+	if split {
+		merged[targetsGuardFile] = c.targetsGuard()
+	}
+	return merged, nil
+}
+
+// targetsGuardFile is built for the targets not listed in the config, the shared files
+// reference the declarations of the target-specific ones, so the package can't be built
+// for those anyway and the guard makes the compiler say why.
+const targetsGuardFile = "targets_guard.go"
+
+func (c *Process) targetsGuard() []byte {
+	terms := make([]string, 0, len(c.suffixes))
+	for _, suffix := range c.suffixes {
+		terms = append(terms, strings.Replace(suffix, "_", " && ", 1))
+	}
+	expr, err := constraint.Parse(fmt.Sprintf("//go:build !(%s)", strings.Join(terms, " || ")))
+	if err != nil {
+		panic(err)
+	}
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "//go:build %s\n", expr)
+	if lines, err := constraint.PlusBuildLines(expr); err == nil {
+		fmt.Fprintln(buf, strings.Join(lines, "\n"))
+	}
+	fmt.Fprintln(buf)
+	c.targets[0].gen.WritePackageHeader(buf)
+	fmt.Fprintf(buf, "// The bindings are generated for %s only, list the target\n", strings.Join(c.suffixes, ", "))
+	fmt.Fprintln(buf, "// in the parser config and generate them again to build the package for it.")
+	fmt.Fprintln(buf, "var _ = bindingsNotGeneratedForThisTarget")
+	filePrefix := filepath.Join(c.outputPath, c.cfg.Generator.PackageName)
+	return c.formatSource(filepath.Join(filePrefix, targetsGuardFile), buf.Bytes())
+}
+
+type goFileDecl struct {
+	key string
+	src []byte
+	// tok is set for a spec of a parenthesized const, var or type block that has been split,
+	// the specs of the same block are joined back when they end up in the same file.
+	tok      string
+	block    int
+	blockDoc []byte
+}
+
+func (c *Process) splitGoFile(merged map[string][]byte, name string, contents [][]byte) error {
+	headers := make([][]byte, len(contents))
+	targetDecls := make([]map[string]goFileDecl, len(contents))
+	var keys []string
+	seenKeys := make(map[string]bool)
+	for i, src := range contents {
+		if src == nil {
+			continue
+		}
+		header, decls, err := parseGoFileDecls(name, src)
+		if err != nil {
+			return fmt.Errorf("process: cannot split %s of %s: %v", name, c.suffixes[i], err)
+		}
+		headers[i] = header
+		targetDecls[i] = make(map[string]goFileDecl, len(decls))
+		for _, decl := range decls {
+			targetDecls[i][decl.key] = decl
+			if !seenKeys[decl.key] {
+				seenKeys[decl.key] = true
+				keys = append(keys, decl.key)
+			}
+		}
+	}
+
+	var shared []goFileDecl
+	targetSpecific := make([][]goFileDecl, len(contents))
+	for _, key := range keys {
+		same := true
+		for i := range contents {
+			decl, ok := targetDecls[i][key]
+			if !ok || !bytes.Equal(decl.src, targetDecls[0][key].src) {
+				same = false
+				break
+			}
+		}
+		if same {
+			shared = append(shared, targetDecls[0][key])
+			continue
+		}
+		for i := range contents {
+			if decl, ok := targetDecls[i][key]; ok {
+				targetSpecific[i] = append(targetSpecific[i], decl)
+			}
+		}
+	}
+	filePrefix := filepath.Join(c.outputPath, c.cfg.Generator.PackageName)
+	if len(shared) > 0 {
+		src := append(append([]byte(nil), firstHeader(headers)...), joinGoFileDecls(shared)...)
+		merged[name] = c.formatSource(filepath.Join(filePrefix, name), src)
+	}
+	for i, decls := range targetSpecific {
+		if len(decls) == 0 {
+			continue
+		}
+		targetName := targetFileName(name, c.suffixes[i])
+		src := append(withoutPackageDoc(headers[i]), joinGoFileDecls(decls)...)
+		merged[targetName] = c.formatSource(filepath.Join(filePrefix, targetName), src)
+	}
+	return nil
+}
+
+// joinGoFileDecls writes the declarations one after another, the consecutive specs
+// of the same block are wrapped into a block again.
+func joinGoFileDecls(decls []goFileDecl) []byte {
+	var buf []byte
+	for i := 0; i < len(decls); i++ {
+		decl := decls[i]
+		if len(decl.tok) == 0 {
+			buf = append(buf, decl.src...)
+			buf = append(buf, "\n\n"...)
+			continue
+		}
+		if len(decl.blockDoc) > 0 {
+			buf = append(buf, decl.blockDoc...)
+			buf = append(buf, '\n')
+		}
+		buf = append(buf, decl.tok+" (\n"...)
+		for ; i < len(decls) && decls[i].tok == decl.tok && decls[i].block == decl.block; i++ {
+			buf = append(buf, decls[i].src...)
+			buf = append(buf, '\n')
+		}
+		i--
+		buf = append(buf, ")\n\n"...)
+	}
+	return buf
+}
+
+// parseGoFileDecls splits the Go source into the header, that is everything up to
+// the first declaration after the imports, and the top-level declarations keyed by
+// the kind and the names declared. The const, var and type blocks are split into
+// their specs, so a spec that differs between the targets doesn't take the others along.
+func parseGoFileDecls(name string, src []byte) (header []byte, decls []goFileDecl, err error) {
+	fset := token.NewFileSet()
+	f, err := goparser.ParseFile(fset, name, src, goparser.ParseComments)
+	if err != nil {
+		return nil, nil, err
+	}
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+	headerEnd := len(src)
+	keyCount := make(map[string]int)
+	uniqueKey := func(key string) string {
+		if keyCount[key]++; keyCount[key] > 1 {
+			key = fmt.Sprintf("%s#%d", key, keyCount[key])
+		}
+		return key
+	}
+	for i, decl := range f.Decls {
+		var key string
+		var doc *ast.CommentGroup
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			if d.Tok == token.IMPORT {
+				continue
+			}
+			doc = d.Doc
+			if splittable(d) {
+				var blockDoc []byte
+				if doc != nil {
+					blockDoc = src[offset(doc.Pos()):offset(doc.End())]
+				}
+				for _, spec := range d.Specs {
+					specSrc, err := specSource(fset, src, spec)
+					if err != nil {
+						return nil, nil, err
+					}
+					decls = append(decls, goFileDecl{
+						key:      uniqueKey(d.Tok.String() + " " + strings.Join(specNames(spec), ",")),
+						src:      specSrc,
+						tok:      d.Tok.String(),
+						block:    i,
+						blockDoc: blockDoc,
+					})
+				}
+				break
+			}
+			var names []string
+			for _, spec := range d.Specs {
+				names = append(names, specNames(spec)...)
+			}
+			key = d.Tok.String() + " " + strings.Join(names, ",")
+		case *ast.FuncDecl:
+			doc = d.Doc
+			key = "func " + d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				key = fmt.Sprintf("func (%s) %s", exprSource(fset, src, d.Recv.List[0].Type), d.Name.Name)
+			}
+		default:
+			continue
+		}
+		start := offset(decl.Pos())
+		if doc != nil {
+			start = offset(doc.Pos())
+		}
+		if start < headerEnd {
+			headerEnd = start
+		}
+		if len(key) == 0 {
+			// the specs have been added already
+			continue
+		}
+		decls = append(decls, goFileDecl{
+			key: uniqueKey(key),
+			src: src[start:offset(decl.End())],
+		})
+	}
+	return src[:headerEnd], decls, nil
+}
+
+// splittable reports whether the specs of the block can be declared apart, that is not
+// the case for a const block relying on the implicit repetition of the previous values.
+func splittable(d *ast.GenDecl) bool {
+	if !d.Lparen.IsValid() {
+		return false
+	}
+	for _, spec := range d.Specs {
+		if s, ok := spec.(*ast.ValueSpec); ok && d.Tok == token.CONST && len(s.Values) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func specNames(spec ast.Spec) []string {
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		return []string{s.Name.Name}
+	case *ast.ValueSpec:
+		names := make([]string, 0, len(s.Names))
+		for _, ident := range s.Names {
+			names = append(names, ident.Name)
+		}
+		return names
+	}
+	return nil
+}
+
+// specSource returns the source of the spec with its comments, the spec itself is printed
+// again so the alignment within the block doesn't make the same specs differ.
+func specSource(fset *token.FileSet, src []byte, spec ast.Spec) ([]byte, error) {
+	var doc, comment *ast.CommentGroup
+	switch s := spec.(type) {
+	case *ast.TypeSpec:
+		doc, comment = s.Doc, s.Comment
+	case *ast.ValueSpec:
+		doc, comment = s.Doc, s.Comment
+	}
+	offset := func(pos token.Pos) int {
+		return fset.Position(pos).Offset
+	}
+	buf := new(bytes.Buffer)
+	if doc != nil {
+		buf.Write(src[offset(doc.Pos()):offset(doc.End())])
+		buf.WriteByte('\n')
+	}
+	if err := printer.Fprint(buf, fset, spec); err != nil {
+		return nil, err
+	}
+	if comment != nil {
+		buf.WriteByte(' ')
+		buf.Write(src[offset(comment.Pos()):offset(comment.End())])
+	}
+	return buf.Bytes(), nil
+}
+
+func exprSource(fset *token.FileSet, src []byte, expr ast.Expr) string {
+	return string(src[fset.Position(expr.Pos()).Offset:fset.Position(expr.End()).Offset])
+}
+
+func firstHeader(headers [][]byte) []byte {
+	for _, header := range headers {
+		if header != nil {
+			return header
+		}
+	}
+	return nil
+}
+
+// withoutPackageDoc returns a copy of the file header without the package doc comment,
+// so the doc is not repeated in the target-specific files.
+func withoutPackageDoc(header []byte) []byte {
+	fset := token.NewFileSet()
+	f, err := goparser.ParseFile(fset, "", header, goparser.ParseComments|goparser.ImportsOnly)
+	if err != nil || f.Doc == nil {
+		return append([]byte(nil), header...)
+	}
+	start := fset.Position(f.Doc.Pos()).Offset
+	end := fset.Position(f.Doc.End()).Offset
+	result := append([]byte(nil), header[:start]...)
+	return append(result, header[end:]...)
+}
+
+// targetFileName inserts the target suffix, e.g. types.go becomes types_linux_arm64.go.
+func targetFileName(name, suffix string) string {
+	ext := filepath.Ext(name)
+	return fmt.Sprintf("%s_%s%s", strings.TrimSuffix(name, ext), suffix, ext)
+}
@@ -0,0 +1,7 @@
+#ifndef ALLOCHOOKS_H
+#define ALLOCHOOKS_H
+
+// join_len returns the total length of the parts.
+int join_len(const char **parts, int n);
+
+#endif
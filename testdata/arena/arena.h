@@ -0,0 +1,11 @@
+#ifndef ARENA_H
+#define ARENA_H
+
+// arena_join_len returns the total length of the parts.
+int arena_join_len(const char **parts, int n);
+// arena_sum_rows returns the sum of the n rows of m values.
+int arena_sum_rows(int **rows, int n, int m);
+// arena_twice returns the value doubled.
+int arena_twice(int v);
+
+#endif
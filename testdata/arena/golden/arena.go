@@ -0,0 +1,74 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package arena
+
+/*
+#include "arena.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+
+// arena_join_len returns the total length of the parts.
+//
+// Join_len function as declared in arena/arena.h:5
+func Join_len(Parts []string, N int32) int32 {
+	cParts, _ := unpackArgSString(Parts)
+	cN, _ := (C.int)(N), cgoAllocsUnknown
+	__ret := C.arena_join_len(cParts, cN)
+	packSString(Parts, cParts)
+	__v := (int32)(__ret)
+	return __v
+}
+
+// Join_len calls arena_join_len with the arguments allocated from the arena.
+func (calls ArenaCalls) Join_len(Parts []string, N int32) int32 {
+	if calls.arena == nil {
+		return Join_len(Parts, N)
+	}
+	cParts, _ := unpackArgSStringInArena(calls.arena, Parts)
+	cN, _ := (C.int)(N), cgoAllocsUnknown
+	__ret := C.arena_join_len(cParts, cN)
+	__v := (int32)(__ret)
+	return __v
+}
+
+// arena_sum_rows returns the sum of the n rows of m values.
+//
+// Sum_rows function as declared in arena/arena.h:7
+func Sum_rows(Rows [][]int32, N int32, M int32) int32 {
+	cRows, _ := unpackArgSSInt32(Rows)
+	cN, _ := (C.int)(N), cgoAllocsUnknown
+	cM, _ := (C.int)(M), cgoAllocsUnknown
+	__ret := C.arena_sum_rows(cRows, cN, cM)
+	packSSInt32(Rows, cRows)
+	__v := (int32)(__ret)
+	return __v
+}
+
+// Sum_rows calls arena_sum_rows with the arguments allocated from the arena.
+func (calls ArenaCalls) Sum_rows(Rows [][]int32, N int32, M int32) int32 {
+	if calls.arena == nil {
+		return Sum_rows(Rows, N, M)
+	}
+	cRows, _ := unpackArgSSInt32InArena(calls.arena, Rows)
+	cN, _ := (C.int)(N), cgoAllocsUnknown
+	cM, _ := (C.int)(M), cgoAllocsUnknown
+	__ret := C.arena_sum_rows(cRows, cN, cM)
+	packSSInt32(Rows, cRows)
+	__v := (int32)(__ret)
+	return __v
+}
+
+// arena_twice returns the value doubled.
+//
+// Twice function as declared in arena/arena.h:9
+func Twice(V int32) int32 {
+	cV, _ := (C.int)(V), cgoAllocsUnknown
+	__ret := C.arena_twice(cV)
+	__v := (int32)(__ret)
+	return __v
+}
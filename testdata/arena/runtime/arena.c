@@ -0,0 +1,24 @@
+#include <string.h>
+#include "arena.h"
+
+int arena_join_len(const char **parts, int n) {
+	int total = 0;
+	for (int i = 0; i < n; i++) {
+		total += strlen(parts[i]);
+	}
+	return total;
+}
+
+int arena_sum_rows(int **rows, int n, int m) {
+	int sum = 0;
+	for (int i = 0; i < n; i++) {
+		for (int j = 0; j < m; j++) {
+			sum += rows[i][j];
+		}
+	}
+	return sum;
+}
+
+int arena_twice(int v) {
+	return v * 2;
+}
@@ -0,0 +1,57 @@
+package arena
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestArena(t *testing.T) {
+	parts := strings.Fields("the parts are allocated from the arena unless they don't fit")
+	var want int32
+	for _, part := range parts {
+		want += int32(len(part))
+	}
+	join := func(a *Arena) {
+		t.Helper()
+		if got := With(a).Join_len(parts, int32(len(parts))); got != want {
+			t.Fatalf("Join_len returned %d, want %d", got, want)
+		}
+	}
+
+	base := LiveAllocations()
+	a := NewArena(64)
+	join(a)
+	spilled := LiveAllocations()
+	if spilled.Allocs-base.Allocs < 2 {
+		t.Fatalf("the call doesn't fit the arena but nothing has spilled: %+v", spilled)
+	}
+
+	a.Reset()
+	grown := LiveAllocations()
+	if grown.Frees-spilled.Frees != spilled.Allocs-base.Allocs {
+		t.Fatalf("Reset has not released the arena and its overflow: %+v", grown)
+	}
+	join(a)
+	if got := LiveAllocations(); got != grown {
+		t.Fatalf("the call allocated from the C heap after the arena has grown: %+v", got)
+	}
+
+	a.Free()
+	if got := LiveAllocations(); got.LiveBytes != base.LiveBytes {
+		t.Fatalf("%d bytes are live after Free", got.LiveBytes-base.LiveBytes)
+	}
+	join(a)
+	a.Reset()
+	join(a)
+	a.Free()
+	if got := LiveAllocations(); got.LiveBytes != base.LiveBytes {
+		t.Fatalf("%d bytes are live after the arena has been reused", got.LiveBytes-base.LiveBytes)
+	}
+}
+
+func TestNilArena(t *testing.T) {
+	rows := [][]int32{{1, 2, 3}, {4, 5, 6}}
+	if got := With(nil).Sum_rows(rows, 2, 3); got != 21 {
+		t.Errorf("Sum_rows returned %d, want 21", got)
+	}
+}
@@ -0,0 +1,111 @@
+#ifndef FOO_H
+#define FOO_H
+
+#define FOO_VERSION 3
+#define FOO_FLAG_A 0x1
+#define FOO_FLAG_B 0x2
+#define FOO_FLAG_AB (FOO_FLAG_A | FOO_FLAG_B)
+#define FOO_DEFAULT_COLOR ((Color)1)
+#define FOO_LIMIT (FOO_VERSION * UNKNOWN_SCALE)
+#define FOO_FLAG_SCALED (FOO_FLAG_B * FOO_VERSION)
+
+// FOO_MAKE_VERSION packs a version triple.
+#define FOO_MAKE_VERSION(major, minor, patch) (((major) << 16) | ((minor) << 8) | (patch))
+// u32 is not accepted, the casts to it become conversions to its Go counterpart.
+typedef unsigned int u32;
+
+// FOO_MAKE_API_VERSION packs a version with casts to a fixed-width type.
+#define FOO_MAKE_API_VERSION(variant, major, minor, patch) \
+	((((u32)(variant)) << 29) | (((u32)(major)) << 22) | (((u32)(minor)) << 12) | ((u32)(patch)))
+#define FOO_API_VERSION_MAJOR(version) (((u32)(version) >> 22) & 0x7F)
+#define FOO_SCALE(x) ((x) * 1.5f)
+#define FOO_CLAMP(x) ((x) < 0 ? 0 : (x))
+
+// The levels get the FooLevel type from their usage with foo_log.
+#define FOO_LEVEL_DEBUG 0
+#define FOO_LEVEL_ERROR 3
+#define FOO_LOG_DEBUG(msg) foo_log(FOO_LEVEL_DEBUG, msg)
+#define FOO_LOG_ERROR(msg) foo_log(FOO_LEVEL_ERROR, msg)
+
+typedef unsigned int FooFlags;
+typedef int FooLevel;
+
+/* Color is a primary color. */
+typedef enum {
+    // Red color.
+    COLOR_RED = 0,
+    COLOR_GREEN = 1,
+    COLOR_BLUE = 2,
+} Color;
+
+typedef enum {
+    FOO_MODE_NONE = 0,
+    FOO_MODE_READ = 1,
+    FOO_MODE_WRITE = 2,
+    FOO_MODE_EXEC = 4,
+} FooMode;
+
+typedef enum {
+    FOO_OPT_A = 1,
+    FOO_OPT_B = 2,
+    FOO_OPT_C = 4,
+    FOO_OPT_AB = 3,
+} FooOpts;
+
+/**
+ * Vec2 is a 2D vector.
+ */
+typedef struct Vec2 {
+    /* horizontal component */
+    float x;
+    float y;
+} Vec2;
+
+typedef union Value {
+    int i;
+    float f;
+    Vec2 v;
+} Value;
+
+typedef struct Packet {
+    unsigned int version : 4;
+    unsigned int kind : 4;
+    unsigned int length : 16;
+    int delta : 8;
+    int id;
+} Packet;
+
+// Header has bit-fields that share the storage unit of the previous member.
+typedef struct Header {
+    char tag;
+    unsigned flags : 4;
+    unsigned size : 20;
+    short crc;
+} Header;
+
+// LogCallback receives log messages.
+// It may be called from any thread.
+typedef void (*LogCallback)(int level, const char *msg, void *user_data);
+
+/**
+ * @brief Adds two numbers.
+ *
+ * @param a the first number
+ * @param[in] b the second number
+ * @return the sum of a and b
+ */
+int foo_add(int a, int b);
+float foo_len(Vec2 v);
+int foo_value_int(Value v);
+Value foo_make_value(float f);
+int foo_packet_delta(Packet p);
+Packet foo_make_packet(int delta);
+int foo_header_size(Header h);
+void foo_set_logger(LogCallback cb, void *user_data);
+void foo_log(FooLevel level, const char *msg);
+void foo_set_flags(FooFlags flags);
+long foo_seek(unsigned long offset);
+int foo_printf(const char *fmt, ...);
+int bar_internal(void);
+
+#endif
@@ -0,0 +1,81 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package foo
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+var symAdd = &librarySymbol{name: "foo_add"}
+
+// Adds two numbers.
+//
+// Parameters:
+//   - a: the first number
+//   - b: the second number
+//
+// Returns: the sum of a and b
+//
+// Add function as declared in basic/foo.h:97
+func Add(A int32, B int32) int32 {
+	return int32(symAdd.call(uintptr(A), uintptr(B)))
+}
+
+var symSet_logger = &librarySymbol{name: "foo_set_logger"}
+
+// Set_logger function as declared in basic/foo.h:104
+func Set_logger(Cb LogCallback, User_data unsafe.Pointer) {
+	symSet_logger.call(uintptr(Cb), uintptr(User_data))
+	runtime.KeepAlive(User_data)
+}
+
+var symLog = &librarySymbol{name: "foo_log"}
+
+// Log function as declared in basic/foo.h:105
+func Log(Level FooLevel, Msg string) {
+	cMsg := cString(Msg)
+	symLog.call(uintptr(Level), uintptr(unsafe.Pointer(cMsg)))
+	runtime.KeepAlive(cMsg)
+}
+
+var symSet_flags = &librarySymbol{name: "foo_set_flags"}
+
+// Set_flags function as declared in basic/foo.h:106
+func Set_flags(Flags FooFlags) {
+	symSet_flags.call(uintptr(Flags))
+}
+
+var symSeek = &librarySymbol{name: "foo_seek"}
+
+// Seek function as declared in basic/foo.h:107
+func Seek(Offset uint64) int64 {
+	return int64(symSeek.call(uintptr(Offset)))
+}
+
+// FOO_MAKE_VERSION packs a version triple.
+//
+// FOO_MAKE_VERSION function-like macro as defined in basic/foo.h:13
+func FOO_MAKE_VERSION(major, minor, patch int64) int64 {
+	return int64((((major) << 16) | ((minor) << 8) | (patch)))
+}
+
+// FOO_MAKE_API_VERSION packs a version with casts to a fixed-width type.
+//
+// FOO_MAKE_API_VERSION function-like macro as defined in basic/foo.h:18
+func FOO_MAKE_API_VERSION(variant, major, minor, patch int64) int64 {
+	return int64((((uint32(variant)) << 29) | ((uint32(major)) << 22) | ((uint32(minor)) << 12) | (uint32(patch))))
+}
+
+// FOO_API_VERSION_MAJOR function-like macro as defined in basic/foo.h:20
+func FOO_API_VERSION_MAJOR(version int64) int64 {
+	return int64(((uint32(version) >> 22) & 0x7F))
+}
+
+// FOO_SCALE function-like macro as defined in basic/foo.h:21
+func FOO_SCALE(x float64) float64 {
+	return float64(((x) * 1.5))
+}
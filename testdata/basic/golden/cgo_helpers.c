@@ -0,0 +1,20 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated 
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+#include "_cgo_export.h"
+#include "cgo_helpers.h"
+
+void LogCallback_4a96404d(int level, char* msg, void* user_data) {
+	logCallback4A96404D(level, msg, user_data);
+}
+
+int foo_printf_int(char* fmt, int arg1) {
+	return foo_printf(fmt, arg1);
+}
+
+int foo_printf_str_double(char* fmt, char* arg1, double arg2) {
+	return foo_printf(fmt, arg1, arg2);
+}
+
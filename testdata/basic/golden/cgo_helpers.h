@@ -0,0 +1,20 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated 
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+#include "foo.h"
+#include <stdlib.h>
+#pragma once
+
+#define __CGOGEN 1
+
+// LogCallback_4a96404d is a proxy for callback LogCallback.
+void LogCallback_4a96404d(int level, char* msg, void* user_data);
+
+// foo_printf_int is a fixed-arity shim for variadic foo_printf.
+int foo_printf_int(char* fmt, int arg1);
+
+// foo_printf_str_double is a fixed-arity shim for variadic foo_printf.
+int foo_printf_str_double(char* fmt, char* arg1, double arg2);
+
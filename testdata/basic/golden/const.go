@@ -0,0 +1,277 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package foo
+
+/*
+#cgo LDFLAGS: -lfoo
+#include "foo.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FOO_VERSION as defined in basic/foo.h:4
+	FOO_VERSION = 3
+	// FOO_LIMIT as defined in basic/foo.h:9
+	FOO_LIMIT = 0
+	// FOO_FLAG_SCALED as defined in basic/foo.h:10
+	FOO_FLAG_SCALED = 6
+)
+
+// Color is a primary color.
+//
+// Color as declared in basic/foo.h:39
+type Color int32
+
+// Color enumeration from basic/foo.h:39
+const (
+	// Red color.
+	COLOR_RED   Color = iota
+	COLOR_GREEN Color = 1
+	COLOR_BLUE  Color = 2
+)
+
+const (
+	// FOO_DEFAULT_COLOR as defined in basic/foo.h:8
+	FOO_DEFAULT_COLOR Color = (Color(1))
+)
+
+// String returns the name of the Color value.
+func (e Color) String() string {
+	switch e {
+	case COLOR_RED:
+		return "COLOR_RED"
+	case COLOR_GREEN:
+		return "COLOR_GREEN"
+	case COLOR_BLUE:
+		return "COLOR_BLUE"
+	}
+	return "Color(" + strconv.FormatInt(int64(e), 10) + ")"
+}
+
+// IsValid reports whether e is a known Color value.
+func (e Color) IsValid() bool {
+	switch e {
+	case COLOR_RED, COLOR_GREEN, COLOR_BLUE:
+		return true
+	}
+	return false
+}
+
+// ColorValues returns all the known Color values.
+func ColorValues() []Color {
+	return []Color{
+		COLOR_RED,
+		COLOR_GREEN,
+		COLOR_BLUE,
+	}
+}
+
+// ParseColor returns the Color value by its name,
+// either the C name or the Go name is accepted.
+func ParseColor(s string) (Color, error) {
+	switch s {
+	case "COLOR_RED":
+		return COLOR_RED, nil
+	case "COLOR_GREEN":
+		return COLOR_GREEN, nil
+	case "COLOR_BLUE":
+		return COLOR_BLUE, nil
+	}
+	return 0, fmt.Errorf("unknown Color name: %q", s)
+}
+
+// FooMode as declared in basic/foo.h:46
+type FooMode int32
+
+// FooMode enumeration from basic/foo.h:46
+const (
+	FOO_MODE_NONE  FooMode = iota
+	FOO_MODE_READ  FooMode = 1
+	FOO_MODE_WRITE FooMode = 2
+	FOO_MODE_EXEC  FooMode = 4
+)
+
+// Has reports whether all the flags of f are set in e.
+func (e FooMode) Has(f FooMode) bool { return e&f == f }
+
+// Set returns e with the flags of f set.
+func (e FooMode) Set(f FooMode) FooMode { return e | f }
+
+// Clear returns e with the flags of f cleared.
+func (e FooMode) Clear(f FooMode) FooMode { return e &^ f }
+
+// String returns the names of the flags set in e joined with |,
+// the unknown bits are written as a hex number.
+func (e FooMode) String() string {
+	if e == 0 {
+		return "FOO_MODE_NONE"
+	}
+	var names []string
+	if e&FOO_MODE_READ == FOO_MODE_READ {
+		names = append(names, "FOO_MODE_READ")
+		e &^= FOO_MODE_READ
+	}
+	if e&FOO_MODE_WRITE == FOO_MODE_WRITE {
+		names = append(names, "FOO_MODE_WRITE")
+		e &^= FOO_MODE_WRITE
+	}
+	if e&FOO_MODE_EXEC == FOO_MODE_EXEC {
+		names = append(names, "FOO_MODE_EXEC")
+		e &^= FOO_MODE_EXEC
+	}
+	if e != 0 {
+		names = append(names, "0x"+strconv.FormatUint(uint64(e), 16))
+	}
+	return strings.Join(names, "|")
+}
+
+// IsValid reports whether e is a known FooMode value.
+func (e FooMode) IsValid() bool {
+	return e&^(FOO_MODE_NONE|FOO_MODE_READ|FOO_MODE_WRITE|FOO_MODE_EXEC) == 0
+}
+
+// FooModeValues returns all the known FooMode values.
+func FooModeValues() []FooMode {
+	return []FooMode{
+		FOO_MODE_NONE,
+		FOO_MODE_READ,
+		FOO_MODE_WRITE,
+		FOO_MODE_EXEC,
+	}
+}
+
+// ParseFooMode returns the FooMode value of the flag names joined with |,
+// either the C names or the Go names are accepted.
+func ParseFooMode(s string) (FooMode, error) {
+	if s == "0" {
+		return 0, nil
+	}
+	var e FooMode
+	for _, name := range strings.Split(s, "|") {
+		flag, err := parseFooModeName(strings.TrimSpace(name))
+		if err != nil {
+			return 0, err
+		}
+		e |= flag
+	}
+	return e, nil
+}
+
+func parseFooModeName(s string) (FooMode, error) {
+	switch s {
+	case "FOO_MODE_NONE":
+		return FOO_MODE_NONE, nil
+	case "FOO_MODE_READ":
+		return FOO_MODE_READ, nil
+	case "FOO_MODE_WRITE":
+		return FOO_MODE_WRITE, nil
+	case "FOO_MODE_EXEC":
+		return FOO_MODE_EXEC, nil
+	}
+	return 0, fmt.Errorf("unknown FooMode name: %q", s)
+}
+
+// FooOpts as declared in basic/foo.h:53
+type FooOpts int32
+
+// FooOpts enumeration from basic/foo.h:53
+const (
+	FOO_OPT_A  FooOpts = 1
+	FOO_OPT_B  FooOpts = 2
+	FOO_OPT_C  FooOpts = 4
+	FOO_OPT_AB FooOpts = 3
+)
+
+// Has reports whether all the flags of f are set in e.
+func (e FooOpts) Has(f FooOpts) bool { return e&f == f }
+
+// Set returns e with the flags of f set.
+func (e FooOpts) Set(f FooOpts) FooOpts { return e | f }
+
+// Clear returns e with the flags of f cleared.
+func (e FooOpts) Clear(f FooOpts) FooOpts { return e &^ f }
+
+// String returns the names of the flags set in e joined with |,
+// the unknown bits are written as a hex number.
+func (e FooOpts) String() string {
+	if e == 0 {
+		return "0"
+	}
+	var names []string
+	if e&FOO_OPT_AB == FOO_OPT_AB {
+		names = append(names, "FOO_OPT_AB")
+		e &^= FOO_OPT_AB
+	}
+	if e&FOO_OPT_A == FOO_OPT_A {
+		names = append(names, "FOO_OPT_A")
+		e &^= FOO_OPT_A
+	}
+	if e&FOO_OPT_B == FOO_OPT_B {
+		names = append(names, "FOO_OPT_B")
+		e &^= FOO_OPT_B
+	}
+	if e&FOO_OPT_C == FOO_OPT_C {
+		names = append(names, "FOO_OPT_C")
+		e &^= FOO_OPT_C
+	}
+	if e != 0 {
+		names = append(names, "0x"+strconv.FormatUint(uint64(e), 16))
+	}
+	return strings.Join(names, "|")
+}
+
+// IsValid reports whether e is a known FooOpts value.
+func (e FooOpts) IsValid() bool {
+	return e&^(FOO_OPT_A|FOO_OPT_B|FOO_OPT_C|FOO_OPT_AB) == 0
+}
+
+// FooOptsValues returns all the known FooOpts values.
+func FooOptsValues() []FooOpts {
+	return []FooOpts{
+		FOO_OPT_A,
+		FOO_OPT_B,
+		FOO_OPT_C,
+		FOO_OPT_AB,
+	}
+}
+
+// ParseFooOpts returns the FooOpts value of the flag names joined with |,
+// either the C names or the Go names are accepted.
+func ParseFooOpts(s string) (FooOpts, error) {
+	if s == "0" {
+		return 0, nil
+	}
+	var e FooOpts
+	for _, name := range strings.Split(s, "|") {
+		flag, err := parseFooOptsName(strings.TrimSpace(name))
+		if err != nil {
+			return 0, err
+		}
+		e |= flag
+	}
+	return e, nil
+}
+
+func parseFooOptsName(s string) (FooOpts, error) {
+	switch s {
+	case "FOO_OPT_A":
+		return FOO_OPT_A, nil
+	case "FOO_OPT_B":
+		return FOO_OPT_B, nil
+	case "FOO_OPT_C":
+		return FOO_OPT_C, nil
+	case "FOO_OPT_AB":
+		return FOO_OPT_AB, nil
+	}
+	return 0, fmt.Errorf("unknown FooOpts name: %q", s)
+}
@@ -0,0 +1,162 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package foo
+
+/*
+#cgo LDFLAGS: -lfoo
+#include "foo.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+import (
+	"runtime"
+	"unsafe"
+)
+
+// Adds two numbers.
+//
+// Parameters:
+//   - a: the first number
+//   - b: the second number
+//
+// Returns: the sum of a and b
+//
+// Add function as declared in basic/foo.h:97
+func Add(A int32, B int32) int32 {
+	cA, _ := (C.int)(A), cgoAllocsUnknown
+	cB, _ := (C.int)(B), cgoAllocsUnknown
+	__ret := C.foo_add(cA, cB)
+	__v := (int32)(__ret)
+	return __v
+}
+
+// Len function as declared in basic/foo.h:98
+func Len(V Vec2) float32 {
+	cV, _ := *(*C.Vec2)(unsafe.Pointer(&V)), cgoAllocsUnknown
+	__ret := C.foo_len(cV)
+	__v := (float32)(__ret)
+	return __v
+}
+
+// Value_int function as declared in basic/foo.h:99
+func Value_int(V Value) int32 {
+	cV, _ := *(*C.Value)(unsafe.Pointer(&V)), cgoAllocsUnknown
+	__ret := C.foo_value_int(cV)
+	__v := (int32)(__ret)
+	return __v
+}
+
+// Make_value function as declared in basic/foo.h:100
+func Make_value(F float32) Value {
+	cF, _ := (C.float)(F), cgoAllocsUnknown
+	__ret := C.foo_make_value(cF)
+	__v := *(*Value)(unsafe.Pointer(&__ret))
+	return __v
+}
+
+// Packet_delta function as declared in basic/foo.h:101
+func Packet_delta(P Packet) int32 {
+	cP, _ := *(*C.Packet)(unsafe.Pointer(&P)), cgoAllocsUnknown
+	__ret := C.foo_packet_delta(cP)
+	__v := (int32)(__ret)
+	return __v
+}
+
+// Make_packet function as declared in basic/foo.h:102
+func Make_packet(Delta int32) Packet {
+	cDelta, _ := (C.int)(Delta), cgoAllocsUnknown
+	__ret := C.foo_make_packet(cDelta)
+	__v := *newPacketRef(unsafe.Pointer(&__ret)).convert()
+	return __v
+}
+
+// Header_size function as declared in basic/foo.h:103
+func Header_size(H Header) int32 {
+	cH, _ := *(*C.Header)(unsafe.Pointer(&H)), cgoAllocsUnknown
+	__ret := C.foo_header_size(cH)
+	__v := (int32)(__ret)
+	return __v
+}
+
+// Set_logger function as declared in basic/foo.h:104
+func Set_logger(Cb LogCallback, User_data unsafe.Pointer) {
+	cCb, _ := Cb.passValue()
+	cUser_data, _ := User_data, cgoAllocsUnknown
+	C.foo_set_logger(cCb, cUser_data)
+}
+
+// Log function as declared in basic/foo.h:105
+func Log(Level FooLevel, Msg string) {
+	cLevel, _ := (C.FooLevel)(Level), cgoAllocsUnknown
+	Msg = safeString(Msg)
+	cMsg, _ := unpackPCharString(Msg)
+	C.foo_log(cLevel, cMsg)
+	runtime.KeepAlive(Msg)
+}
+
+// Set_flags function as declared in basic/foo.h:106
+func Set_flags(Flags FooFlags) {
+	cFlags, _ := (C.FooFlags)(Flags), cgoAllocsUnknown
+	C.foo_set_flags(cFlags)
+}
+
+// Seek function as declared in basic/foo.h:107
+func Seek(Offset uint64) int64 {
+	cOffset, _ := (C.ulong)(Offset), cgoAllocsUnknown
+	__ret := C.foo_seek(cOffset)
+	__v := (int64)(__ret)
+	return __v
+}
+
+// Printf_int function as declared in basic/foo.h:108
+func Printf_int(Fmt string, Arg1 int32) int32 {
+	Fmt = safeString(Fmt)
+	cFmt, _ := unpackPCharString(Fmt)
+	cArg1, _ := (C.int)(Arg1), cgoAllocsUnknown
+	__ret := C.foo_printf_int(cFmt, cArg1)
+	runtime.KeepAlive(Fmt)
+	__v := (int32)(__ret)
+	return __v
+}
+
+// Printf_str_double function as declared in basic/foo.h:108
+func Printf_str_double(Fmt string, Arg1 string, Arg2 float64) int32 {
+	Fmt = safeString(Fmt)
+	cFmt, _ := unpackPCharString(Fmt)
+	Arg1 = safeString(Arg1)
+	cArg1, _ := unpackPCharString(Arg1)
+	cArg2, _ := (C.double)(Arg2), cgoAllocsUnknown
+	__ret := C.foo_printf_str_double(cFmt, cArg1, cArg2)
+	runtime.KeepAlive(Arg1)
+	runtime.KeepAlive(Fmt)
+	__v := (int32)(__ret)
+	return __v
+}
+
+// FOO_MAKE_VERSION packs a version triple.
+//
+// FOO_MAKE_VERSION function-like macro as defined in basic/foo.h:13
+func FOO_MAKE_VERSION(major, minor, patch int64) int64 {
+	return int64((((major) << 16) | ((minor) << 8) | (patch)))
+}
+
+// FOO_MAKE_API_VERSION packs a version with casts to a fixed-width type.
+//
+// FOO_MAKE_API_VERSION function-like macro as defined in basic/foo.h:18
+func FOO_MAKE_API_VERSION(variant, major, minor, patch int64) int64 {
+	return int64((((uint32(variant)) << 29) | ((uint32(major)) << 22) | ((uint32(minor)) << 12) | (uint32(patch))))
+}
+
+// FOO_API_VERSION_MAJOR function-like macro as defined in basic/foo.h:20
+func FOO_API_VERSION_MAJOR(version int64) int64 {
+	return int64(((uint32(version) >> 22) & 0x7F))
+}
+
+// FOO_SCALE function-like macro as defined in basic/foo.h:21
+func FOO_SCALE(x float64) float64 {
+	return float64(((x) * 1.5))
+}
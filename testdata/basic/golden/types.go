@@ -0,0 +1,145 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package foo
+
+/*
+#cgo LDFLAGS: -lfoo
+#include "foo.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+import "unsafe"
+
+// FooFlags type as declared in basic/foo.h:30
+type FooFlags uint32
+
+const (
+	// FOO_FLAG_A as defined in basic/foo.h:5
+	FOO_FLAG_A FooFlags = 0x1
+	// FOO_FLAG_B as defined in basic/foo.h:6
+	FOO_FLAG_B FooFlags = 0x2
+	// FOO_FLAG_AB as defined in basic/foo.h:7
+	FOO_FLAG_AB FooFlags = (FOO_FLAG_A | FOO_FLAG_B)
+)
+
+// FooLevel type as declared in basic/foo.h:31
+type FooLevel int32
+
+const (
+	// The levels get the FooLevel type from their usage with foo_log.
+	//
+	// FOO_LEVEL_DEBUG as defined in basic/foo.h:25
+	FOO_LEVEL_DEBUG FooLevel = 0
+	// FOO_LEVEL_ERROR as defined in basic/foo.h:26
+	FOO_LEVEL_ERROR FooLevel = 3
+)
+
+// Vec2 as declared in basic/foo.h:62
+type gVec2 struct {
+	gX            float32
+	gY            float32
+	ref739ef4d    *C.Vec2
+	allocs739ef4d interface{}
+}
+
+// Vec2 is a 2D vector.
+type Vec2 struct {
+	// horizontal component
+	X float32
+	Y float32
+}
+
+// Vec2 layout as computed for the target.
+const (
+	SizeofVec2    = 8
+	AlignofVec2   = 4
+	OffsetofVec2X = 0
+	OffsetofVec2Y = 4
+)
+
+// Value as declared in basic/foo.h:68
+type Value [SizeofValue]byte
+
+// Value layout as computed for the target.
+const (
+	SizeofValue  = 8
+	AlignofValue = 4
+)
+
+// Packet as declared in basic/foo.h:76
+type gPacket struct {
+	gVersion       uint32
+	gKind          uint32
+	gLength        uint32
+	gDelta         int32
+	gId            int32
+	ref3beda142    *C.Packet
+	allocs3beda142 interface{}
+}
+type Packet struct {
+	bitfield0 [4]byte
+	Id        int32
+}
+
+// Packet layout as computed for the target.
+const (
+	SizeofPacket     = 8
+	AlignofPacket    = 4
+	OffsetofPacketId = 4
+)
+
+// Header as declared in basic/foo.h:84
+type gHeader struct {
+	gTag           byte
+	gFlags         uint32
+	gSize          uint32
+	gCrc           int16
+	ref69deadf7    *C.Header
+	allocs69deadf7 interface{}
+}
+
+// Header has bit-fields that share the storage unit of the previous member.
+type Header struct {
+	_         [0]uint32
+	Tag       byte
+	bitfield1 [3]byte
+	Crc       int16
+	_         [2]byte
+}
+
+// Header layout as computed for the target.
+const (
+	SizeofHeader      = 8
+	AlignofHeader     = 4
+	OffsetofHeaderTag = 0
+	OffsetofHeaderCrc = 4
+)
+
+// LogCallback receives log messages.
+// It may be called from any thread.
+//
+// LogCallback type as declared in basic/foo.h:88
+type LogCallback func(Level int32, Msg string, User_data unsafe.Pointer)
+
+// An out of bounds index or an overflow of uintptr reported by the compiler
+// here means that the layout of the type differs from the computed one.
+func _() {
+	var x [1]struct{}
+	// Vec2
+	_ = x[SizeofVec2-unsafe.Sizeof(C.Vec2{})]
+	_ = x[OffsetofVec2X-unsafe.Offsetof(C.Vec2{}.x)]
+	_ = x[OffsetofVec2Y-unsafe.Offsetof(C.Vec2{}.y)]
+	// Value
+	_ = x[SizeofValue-unsafe.Sizeof(C.Value{})]
+	// Packet
+	_ = x[SizeofPacket-unsafe.Sizeof(C.Packet{})]
+	_ = x[OffsetofPacketId-unsafe.Offsetof(C.Packet{}.id)]
+	// Header
+	_ = x[SizeofHeader-unsafe.Sizeof(C.Header{})]
+	_ = x[OffsetofHeaderTag-unsafe.Offsetof(C.Header{}.tag)]
+	_ = x[OffsetofHeaderCrc-unsafe.Offsetof(C.Header{}.crc)]
+}
@@ -0,0 +1,45 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package foo
+
+/*
+#cgo LDFLAGS: -lfoo
+#include "foo.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+import "unsafe"
+
+// AsI returns a pointer to the i member of the union.
+func (u *Value) AsI() *int32 {
+	return (*int32)(unsafe.Pointer(u))
+}
+
+// SetI sets the i member of the union.
+func (u *Value) SetI(v int32) {
+	*(*int32)(unsafe.Pointer(u)) = v
+}
+
+// AsF returns a pointer to the f member of the union.
+func (u *Value) AsF() *float32 {
+	return (*float32)(unsafe.Pointer(u))
+}
+
+// SetF sets the f member of the union.
+func (u *Value) SetF(v float32) {
+	*(*float32)(unsafe.Pointer(u)) = v
+}
+
+// AsV returns a pointer to the v member of the union.
+func (u *Value) AsV() *Vec2 {
+	return (*Vec2)(unsafe.Pointer(u))
+}
+
+// SetV sets the v member of the union.
+func (u *Value) SetV(v Vec2) {
+	*(*Vec2)(unsafe.Pointer(u)) = v
+}
@@ -0,0 +1,15 @@
+#ifndef CALLBACKS_H
+#define CALLBACKS_H
+
+// Visitor is called with each value and the user data it has been registered with.
+typedef int (*Visitor)(int value, void *user_data);
+
+// cb_each calls the visitor for the values from 0 to n-1 and returns the sum of the results.
+int cb_each(int n, Visitor fn, void *user_data);
+
+// cb_subscribe keeps the visitor in the slot, it is called by cb_emit with ctx as its user data.
+void cb_subscribe(int slot, Visitor fn, void *ctx);
+// cb_emit calls the visitor kept in the slot and returns its result, or -1 if there is none.
+int cb_emit(int slot, int value);
+
+#endif
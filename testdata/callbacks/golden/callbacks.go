@@ -0,0 +1,47 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package callbacks
+
+/*
+#include "callbacks.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+import "unsafe"
+
+// cb_each calls the visitor for the values from 0 to n-1 and returns the sum of the results.
+//
+// Each function as declared in callbacks/callbacks.h:8
+func Each(N int32, Fn Visitor, User_data unsafe.Pointer) int32 {
+	cN, _ := (C.int)(N), cgoAllocsUnknown
+	cFn, _ := Fn.passValue()
+	cUser_data, _ := User_data, cgoAllocsUnknown
+	__ret := C.cb_each(cN, cFn, cUser_data)
+	__v := (int32)(__ret)
+	return __v
+}
+
+// cb_subscribe keeps the visitor in the slot, it is called by cb_emit with ctx as its user data.
+//
+// Subscribe function as declared in callbacks/callbacks.h:11
+func Subscribe(Slot int32, Fn Visitor, Ctx unsafe.Pointer) {
+	cSlot, _ := (C.int)(Slot), cgoAllocsUnknown
+	cFn, _ := Fn.passValue()
+	cCtx, _ := Ctx, cgoAllocsUnknown
+	C.cb_subscribe(cSlot, cFn, cCtx)
+}
+
+// cb_emit calls the visitor kept in the slot and returns its result, or -1 if there is none.
+//
+// Emit function as declared in callbacks/callbacks.h:13
+func Emit(Slot int32, Value int32) int32 {
+	cSlot, _ := (C.int)(Slot), cgoAllocsUnknown
+	cValue, _ := (C.int)(Value), cgoAllocsUnknown
+	__ret := C.cb_emit(cSlot, cValue)
+	__v := (int32)(__ret)
+	return __v
+}
@@ -0,0 +1,12 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated 
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+#include "_cgo_export.h"
+#include "cgo_helpers.h"
+
+int Visitor_558d803(int value, void* user_data) {
+	return visitor558D803(value, user_data);
+}
+
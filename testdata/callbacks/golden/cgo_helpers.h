@@ -0,0 +1,14 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated 
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+#include "callbacks.h"
+#include <stdlib.h>
+#pragma once
+
+#define __CGOGEN 1
+
+// Visitor_558d803 is a proxy for callback Visitor.
+int Visitor_558d803(int value, void* user_data);
+
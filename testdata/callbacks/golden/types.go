@@ -0,0 +1,19 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package callbacks
+
+/*
+#include "callbacks.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+import "unsafe"
+
+// Visitor is called with each value and the user data it has been registered with.
+//
+// Visitor type as declared in callbacks/callbacks.h:5
+type Visitor func(Value int32, User_data unsafe.Pointer) int32
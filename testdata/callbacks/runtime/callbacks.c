@@ -0,0 +1,36 @@
+#include <pthread.h>
+#include "callbacks.h"
+
+#define SLOTS 64
+
+static struct {
+	Visitor fn;
+	void *ctx;
+} slots[SLOTS];
+static pthread_mutex_t slots_mu = PTHREAD_MUTEX_INITIALIZER;
+
+int cb_each(int n, Visitor fn, void *user_data) {
+	int sum = 0;
+	for (int i = 0; i < n; i++) {
+		sum += fn(i, user_data);
+	}
+	return sum;
+}
+
+void cb_subscribe(int slot, Visitor fn, void *ctx) {
+	pthread_mutex_lock(&slots_mu);
+	slots[slot].fn = fn;
+	slots[slot].ctx = ctx;
+	pthread_mutex_unlock(&slots_mu);
+}
+
+int cb_emit(int slot, int value) {
+	pthread_mutex_lock(&slots_mu);
+	Visitor fn = slots[slot].fn;
+	void *ctx = slots[slot].ctx;
+	pthread_mutex_unlock(&slots_mu);
+	if (fn == NULL) {
+		return -1;
+	}
+	return fn(value, ctx);
+}
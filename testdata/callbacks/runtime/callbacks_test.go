@@ -0,0 +1,76 @@
+package callbacks
+
+import (
+	"sync"
+	"unsafe"
+	"testing"
+)
+
+func TestRegistry(t *testing.T) {
+	const goroutines = 32
+	var wg sync.WaitGroup
+	errs := make(chan string, goroutines)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(k int32) {
+			defer wg.Done()
+			var calls int32
+			fn := Visitor(func(value int32, _ unsafe.Pointer) int32 {
+				calls++
+				return value * k
+			})
+			handle := fn.Register()
+			if lookupCallback(handle) == nil {
+				errs <- "callback is not found after Register"
+				return
+			}
+			if sum := Each(10, fn, handle); sum != 45*k {
+				errs <- "Each called the callback of another goroutine"
+			}
+			if calls != 10 {
+				errs <- "Each did not call the callback for every value"
+			}
+			UnregisterCallback(handle)
+			UnregisterCallback(handle)
+			if lookupCallback(handle) != nil {
+				errs <- "callback is found after UnregisterCallback"
+			}
+		}(int32(g + 1))
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	const slots = 8
+	handles := make([]unsafe.Pointer, slots)
+	for i := range handles {
+		offset := int32(i * 100)
+		fn := Visitor(func(value int32, _ unsafe.Pointer) int32 {
+			return offset + value
+		})
+		handles[i] = fn.Register()
+		Subscribe(int32(i), fn, handles[i])
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < slots; i++ {
+		wg.Add(1)
+		go func(slot int32) {
+			defer wg.Done()
+			if got := Emit(slot, 7); got != slot*100+7 {
+				t.Errorf("Emit(%d) returned %d, want %d", slot, got, slot*100+7)
+			}
+		}(int32(i))
+	}
+	wg.Wait()
+	for i, handle := range handles {
+		Subscribe(int32(i), nil, nil)
+		UnregisterCallback(handle)
+	}
+	if got := Emit(0, 7); got != -1 {
+		t.Errorf("Emit returned %d after unsubscribing, want -1", got)
+	}
+}
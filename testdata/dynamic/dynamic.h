@@ -0,0 +1,15 @@
+#ifndef DYNAMIC_H
+#define DYNAMIC_H
+
+typedef struct Conn {
+	int id;
+	int ready;
+} Conn;
+
+// dyn_open opens a connection with the name, the name is not kept.
+Conn *dyn_open(const char *name, _Bool verbose);
+_Bool dyn_ready(const Conn *handle);
+void *dyn_buffer(Conn *handle);
+int dyn_send(Conn *handle, const char *msg, int n);
+
+#endif
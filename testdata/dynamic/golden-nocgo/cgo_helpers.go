@@ -0,0 +1,95 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package dynamic
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Loader opens a shared library and looks up its symbols, it's implemented
+// on top of dlopen and dlsym, or LoadLibrary and GetProcAddress on Windows.
+type Loader interface {
+	Open(path string) (handle uintptr, err error)
+	Lookup(handle uintptr, name string) (addr uintptr, err error)
+}
+
+// Trampoline calls the C function at addr passing the args in the machine words,
+// it has the signature of purego.SyscallN and syscall.SyscallN on Windows.
+type Trampoline func(addr uintptr, args ...uintptr) (r1, r2, err uintptr)
+
+var library struct {
+	mux    sync.RWMutex
+	handle uintptr
+	loader Loader
+	call   Trampoline
+}
+
+// Load opens the shared library at path with the loader, the functions
+// of the package look up their symbols in it and call them with call.
+func Load(loader Loader, call Trampoline, path string) error {
+	library.mux.Lock()
+	defer library.mux.Unlock()
+	if library.loader != nil {
+		return errors.New("dynamic: the library is already loaded")
+	}
+	handle, err := loader.Open(path)
+	if err != nil {
+		return err
+	}
+	library.handle = handle
+	library.loader = loader
+	library.call = call
+	return nil
+}
+
+// librarySymbol is a C function looked up in the library on the first call.
+type librarySymbol struct {
+	name string
+	addr uintptr
+}
+
+func (s *librarySymbol) call(args ...uintptr) uintptr {
+	library.mux.RLock()
+	handle, loader, call := library.handle, library.loader, library.call
+	library.mux.RUnlock()
+	if loader == nil {
+		panic("dynamic: the library is not loaded, call Load first")
+	}
+	addr := atomic.LoadUintptr(&s.addr)
+	if addr == 0 {
+		var err error
+		if addr, err = loader.Lookup(handle, s.name); err != nil {
+			panic(fmt.Sprintf("dynamic: %s: %v", s.name, err))
+		}
+		atomic.StoreUintptr(&s.addr, addr)
+	}
+	r1, _, _ := call(addr, args...)
+	return r1
+}
+
+// cString returns a NUL-terminated copy of s, it must be kept alive until the C call returns.
+func cString(s string) *byte {
+	b := make([]byte, len(s)+1)
+	copy(b, s)
+	return &b[0]
+}
+
+// boolWord converts v to the machine word passed to C.
+func boolWord(v bool) uintptr {
+	if v {
+		return 1
+	}
+	return 0
+}
+
+// ptrOf converts the address returned by a C function to a pointer.
+func ptrOf(addr uintptr) unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&addr))
+}
@@ -0,0 +1,52 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package dynamic
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+var symOpen = &librarySymbol{name: "dyn_open"}
+
+// dyn_open opens a connection with the name, the name is not kept.
+//
+// Open function as declared in dynamic/dynamic.h:10
+func Open(Name string, Verbose bool) *Conn {
+	cName := cString(Name)
+	__ret := symOpen.call(uintptr(unsafe.Pointer(cName)), boolWord(bool(Verbose)))
+	runtime.KeepAlive(cName)
+	return (*Conn)(ptrOf(__ret))
+}
+
+var symReady = &librarySymbol{name: "dyn_ready"}
+
+// Ready function as declared in dynamic/dynamic.h:11
+func Ready(Handle *Conn) bool {
+	__ret := symReady.call(uintptr(unsafe.Pointer(Handle)))
+	runtime.KeepAlive(Handle)
+	return bool(__ret&0xff != 0)
+}
+
+var symBuffer = &librarySymbol{name: "dyn_buffer"}
+
+// Buffer function as declared in dynamic/dynamic.h:12
+func Buffer(Handle *Conn) unsafe.Pointer {
+	__ret := symBuffer.call(uintptr(unsafe.Pointer(Handle)))
+	runtime.KeepAlive(Handle)
+	return ptrOf(__ret)
+}
+
+var symSend = &librarySymbol{name: "dyn_send"}
+
+// Send function as declared in dynamic/dynamic.h:13
+func Send(Handle *Conn, Msg string, N int32) int32 {
+	cMsg := cString(Msg)
+	__ret := symSend.call(uintptr(unsafe.Pointer(Handle)), uintptr(unsafe.Pointer(cMsg)), uintptr(N))
+	runtime.KeepAlive(Handle)
+	runtime.KeepAlive(cMsg)
+	return int32(__ret)
+}
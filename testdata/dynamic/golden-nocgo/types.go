@@ -0,0 +1,12 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package dynamic
+
+// Conn as declared in dynamic/dynamic.h:7
+type Conn struct {
+	Id    int32
+	Ready int32
+}
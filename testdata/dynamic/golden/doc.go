@@ -0,0 +1,9 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+/*
+Package dynamic is a golden-file fixture of the bindings called through a trampoline.
+*/
+package dynamic
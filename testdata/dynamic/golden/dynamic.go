@@ -0,0 +1,51 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package dynamic
+
+/*
+#include "dynamic.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+import "unsafe"
+
+// dyn_open opens a connection with the name, the name is not kept.
+//
+// Open function as declared in dynamic/dynamic.h:10
+func Open(Name string, Verbose bool) *Conn {
+	cName, _ := unpackPCharString(Name)
+	cVerbose, _ := (C._Bool)(Verbose), cgoAllocsUnknown
+	__ret := C.dyn_open(cName, cVerbose)
+	__v := newConnRef(unsafe.Pointer(__ret)).convert()
+	return __v
+}
+
+// Ready function as declared in dynamic/dynamic.h:11
+func Ready(Handle *Conn) bool {
+	cHandle, _ := (*C.Conn)(unsafe.Pointer(Handle)), cgoAllocsUnknown
+	__ret := C.dyn_ready(cHandle)
+	__v := (bool)(__ret)
+	return __v
+}
+
+// Buffer function as declared in dynamic/dynamic.h:12
+func Buffer(Handle *Conn) unsafe.Pointer {
+	cHandle, _ := (*C.Conn)(unsafe.Pointer(Handle)), cgoAllocsUnknown
+	__ret := C.dyn_buffer(cHandle)
+	__v := *(*unsafe.Pointer)(unsafe.Pointer(&__ret))
+	return __v
+}
+
+// Send function as declared in dynamic/dynamic.h:13
+func Send(Handle *Conn, Msg string, N int32) int32 {
+	cHandle, _ := (*C.Conn)(unsafe.Pointer(Handle)), cgoAllocsUnknown
+	cMsg, _ := unpackPCharString(Msg)
+	cN, _ := (C.int)(N), cgoAllocsUnknown
+	__ret := C.dyn_send(cHandle, cMsg, cN)
+	__v := (int32)(__ret)
+	return __v
+}
@@ -0,0 +1,25 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package dynamic
+
+/*
+#include "dynamic.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+
+// Conn as declared in dynamic/dynamic.h:7
+type gConn struct {
+	gId            int32
+	gReady         int32
+	refba1aaea4    *C.Conn
+	allocsba1aaea4 interface{}
+}
+type Conn struct {
+	Id    int32
+	Ready int32
+}
@@ -0,0 +1,164 @@
+package dynamic
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+	"unsafe"
+)
+
+type fakeLoader struct {
+	symbols map[string]uintptr
+	lookups map[string]int
+}
+
+func (l *fakeLoader) Open(path string) (uintptr, error) {
+	if path != "libdynamic.so" {
+		return 0, errors.New("no such library")
+	}
+	return 1, nil
+}
+
+func (l *fakeLoader) Lookup(handle uintptr, name string) (uintptr, error) {
+	addr, ok := l.symbols[name]
+	if !ok || handle != 1 {
+		return 0, errors.New("no such symbol")
+	}
+	l.lookups[name]++
+	return addr, nil
+}
+
+type call struct {
+	addr uintptr
+	args []uintptr
+	// strs are the strings the pointer args refer to, read during the call
+	strs map[int]string
+}
+
+// fake records the calls made through the trampoline, it returns the results
+// queued in rets and reads the strings passed after a GC, so a string that is
+// not kept alive until the call returns would be reported as garbage.
+type fake struct {
+	calls   []call
+	rets    []uintptr
+	strArgs map[uintptr][]int
+}
+
+func (f *fake) trampoline(addr uintptr, args ...uintptr) (r1, r2, err uintptr) {
+	runtime.GC()
+	c := call{
+		addr: addr,
+		args: append([]uintptr(nil), args...),
+		strs: make(map[int]string),
+	}
+	for _, i := range f.strArgs[addr] {
+		c.strs[i] = readString(args[i])
+	}
+	f.calls = append(f.calls, c)
+	r1, f.rets = f.rets[0], f.rets[1:]
+	return r1, 0, 0
+}
+
+func readString(addr uintptr) string {
+	var b []byte
+	for p := addr; *(*byte)(unsafe.Pointer(p)) != 0; p++ {
+		b = append(b, *(*byte)(unsafe.Pointer(p)))
+	}
+	return string(b)
+}
+
+func TestTrampoline(t *testing.T) {
+	loader := &fakeLoader{
+		symbols: map[string]uintptr{
+			"dyn_open":   0x100,
+			"dyn_ready":  0x200,
+			"dyn_buffer": 0x300,
+			"dyn_send":   0x400,
+		},
+		lookups: make(map[string]int),
+	}
+	conn := &Conn{Id: 7}
+	buf := make([]byte, 16)
+	f := &fake{
+		rets: []uintptr{
+			uintptr(unsafe.Pointer(conn)),
+			0,
+			0x100, // only the low byte of a C bool is defined
+			0x101,
+			uintptr(unsafe.Pointer(&buf[0])),
+			5,
+		},
+		strArgs: map[uintptr][]int{
+			0x100: {0},
+			0x400: {1},
+		},
+	}
+	if err := Load(loader, f.trampoline, "libdynamic.so"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Load(loader, f.trampoline, "libdynamic.so"); err == nil {
+		t.Error("the library has been loaded twice")
+	}
+
+	if got := Open(string([]byte("first")), true); got != conn {
+		t.Errorf("Open returned %p, want %p", got, conn)
+	}
+	if got := Open(string([]byte("second")), false); got != nil {
+		t.Errorf("Open returned %p for a NULL result", got)
+	}
+	if Ready(conn) {
+		t.Error("Ready is true for a word with the low byte unset")
+	}
+	if !Ready(conn) {
+		t.Error("Ready is false for a word with the low byte set")
+	}
+	if got := Buffer(conn); got != unsafe.Pointer(&buf[0]) {
+		t.Errorf("Buffer returned %p, want %p", got, &buf[0])
+	}
+	if got := Send(conn, string([]byte("hello")), 5); got != 5 {
+		t.Errorf("Send returned %d, want 5", got)
+	}
+
+	want := []call{
+		{addr: 0x100, args: []uintptr{0, 1}, strs: map[int]string{0: "first"}},
+		{addr: 0x100, args: []uintptr{0, 0}, strs: map[int]string{0: "second"}},
+		{addr: 0x200, args: []uintptr{uintptr(unsafe.Pointer(conn))}},
+		{addr: 0x200, args: []uintptr{uintptr(unsafe.Pointer(conn))}},
+		{addr: 0x300, args: []uintptr{uintptr(unsafe.Pointer(conn))}},
+		{addr: 0x400, args: []uintptr{uintptr(unsafe.Pointer(conn)), 0, 5}, strs: map[int]string{1: "hello"}},
+	}
+	if len(f.calls) != len(want) {
+		t.Fatalf("%d calls have been made, want %d", len(f.calls), len(want))
+	}
+	for i, c := range f.calls {
+		w := want[i]
+		if c.addr != w.addr {
+			t.Errorf("call %d: addr is %#x, want %#x", i, c.addr, w.addr)
+		}
+		if len(c.args) != len(w.args) {
+			t.Errorf("call %d: %d words have been passed, want %d", i, len(c.args), len(w.args))
+			continue
+		}
+		for j, arg := range c.args {
+			if _, ok := w.strs[j]; ok {
+				// the address of the string copy is not known in advance
+				continue
+			}
+			if arg != w.args[j] {
+				t.Errorf("call %d: word %d is %#x, want %#x", i, j, arg, w.args[j])
+			}
+		}
+		for j, s := range w.strs {
+			if c.strs[j] != s {
+				t.Errorf("call %d: string %d is %q, want %q", i, j, c.strs[j], s)
+			}
+		}
+	}
+	for name, n := range loader.lookups {
+		if n != 1 {
+			t.Errorf("%s has been looked up %d times, want once", name, n)
+		}
+	}
+	runtime.KeepAlive(conn)
+	runtime.KeepAlive(buf)
+}
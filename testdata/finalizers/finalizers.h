@@ -0,0 +1,13 @@
+#ifndef FINALIZERS_H
+#define FINALIZERS_H
+
+typedef struct Item {
+	const char *name;
+	const char **tags;
+	int tags_len;
+} Item;
+
+// item_tags_len returns the number of tags set on the item.
+int item_tags_len(const Item *item);
+
+#endif
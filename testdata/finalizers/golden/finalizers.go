@@ -0,0 +1,24 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package finalizers
+
+/*
+#include "finalizers.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+import "unsafe"
+
+// item_tags_len returns the number of tags set on the item.
+//
+// Tags_len function as declared in finalizers/finalizers.h:11
+func Tags_len(Item *Item) int32 {
+	cItem, _ := (*C.Item)(unsafe.Pointer(Item)), cgoAllocsUnknown
+	__ret := C.item_tags_len(cItem)
+	__v := (int32)(__ret)
+	return __v
+}
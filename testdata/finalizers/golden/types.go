@@ -0,0 +1,27 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package finalizers
+
+/*
+#include "finalizers.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+
+// Item as declared in finalizers/finalizers.h:8
+type gItem struct {
+	gName          string
+	gTags          []string
+	gTags_len      int32
+	refbf298a20    *C.Item
+	allocsbf298a20 interface{}
+}
+type Item struct {
+	Name     string
+	Tags     *string
+	Tags_len int32
+}
@@ -0,0 +1,5 @@
+#include "finalizers.h"
+
+int item_tags_len(const Item *item) {
+	return item->tags_len;
+}
@@ -0,0 +1,90 @@
+//go:build cforgo_debug
+// +build cforgo_debug
+
+package finalizers
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// captureStderr redirects os.Stderr into the returned channel of lines.
+func captureStderr(t *testing.T) (lines <-chan string, restore func()) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	ch := make(chan string, 16)
+	go func() {
+		s := bufio.NewScanner(r)
+		for s.Scan() {
+			ch <- s.Text()
+		}
+		close(ch)
+	}()
+	return ch, func() {
+		os.Stderr = stderr
+		w.Close()
+	}
+}
+
+func allocItem() {
+	item, _ := AllocItem("item", []string{"a", "b"}, 2)
+	if n := Tags_len(item); n != 2 {
+		panic("unexpected number of tags")
+	}
+}
+
+func TestUnfreedReport(t *testing.T) {
+	lines, restore := captureStderr(t)
+	allocItem()
+	deadline := time.After(5 * time.Second)
+	for {
+		runtime.GC()
+		select {
+		case line := <-lines:
+			restore()
+			if !strings.HasPrefix(line, "finalizers: Item at 0x") ||
+				!strings.HasSuffix(line, "was collected without an explicit Free") {
+				t.Fatalf("unexpected report: %q", line)
+			}
+			return
+		case <-deadline:
+			restore()
+			t.Fatal("the unfreed Item has not been reported")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestFreeDisarms(t *testing.T) {
+	var reported int32
+	report := reportUnfreed
+	reportUnfreed = func(name string, ptr uintptr) { atomic.AddInt32(&reported, 1) }
+	defer func() { reportUnfreed = report }()
+
+	item, allocs := AllocItem("item", []string{"a", "b"}, 2)
+	if allocs.IsEmpty() {
+		t.Fatal("the tags have not been allocated in C memory")
+	}
+	item.Free()
+	if !allocs.IsEmpty() {
+		t.Error("Free has not released the allocations")
+	}
+	item.Free()
+	item = nil
+	for i := 0; i < 5; i++ {
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := atomic.LoadInt32(&reported); n > 0 {
+		t.Errorf("%d freed items have been reported", n)
+	}
+}
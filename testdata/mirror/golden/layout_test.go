@@ -0,0 +1,46 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package mirror
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestPointLayout(t *testing.T) {
+	names := []string{"size", "x", "y"}
+	want := cLayoutOfPoint()
+	got := []uintptr{
+		unsafe.Sizeof(Point{}),
+		unsafe.Offsetof(Point{}.X),
+		unsafe.Offsetof(Point{}.Y),
+	}
+	for i, name := range names {
+		if got[i] != want[i] {
+			t.Errorf("Point: %s is %d in Go, %d in C", name, got[i], want[i])
+		}
+	}
+}
+
+func TestRecordLayout(t *testing.T) {
+	names := []string{"size", "tag", "weight", "origin", "name", "id", "notify", "data"}
+	want := cLayoutOfRecord()
+	got := []uintptr{
+		unsafe.Sizeof(Record{}),
+		unsafe.Offsetof(Record{}.Tag),
+		unsafe.Offsetof(Record{}.Weight),
+		unsafe.Offsetof(Record{}.Origin),
+		unsafe.Offsetof(Record{}.Name),
+		unsafe.Offsetof(Record{}.Id),
+		unsafe.Offsetof(Record{}.Notify),
+		unsafe.Offsetof(Record{}.Data),
+	}
+	for i, name := range names {
+		if got[i] != want[i] {
+			t.Errorf("Record: %s is %d in Go, %d in C", name, got[i], want[i])
+		}
+	}
+}
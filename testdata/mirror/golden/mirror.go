@@ -0,0 +1,39 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package mirror
+
+/*
+#include "mirror.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+import "unsafe"
+
+// Update function as declared in mirror/mirror.h:22
+func Update(R []Record, Delta int32) int32 {
+	cR, _ := (*C.Record)(unsafe.Pointer((*sliceHeader)(unsafe.Pointer(&R)).Data)), cgoAllocsUnknown
+	cDelta, _ := (C.int)(Delta), cgoAllocsUnknown
+	__ret := C.mirror_update(cR, cDelta)
+	__v := (int32)(__ret)
+	return __v
+}
+
+// Find function as declared in mirror/mirror.h:23
+func Find(Name string) *Record {
+	cName, _ := unpackPCharString(Name)
+	__ret := C.mirror_find(cName)
+	__v := *(**Record)(unsafe.Pointer(&__ret))
+	return __v
+}
+
+// Origin function as declared in mirror/mirror.h:24
+func Origin(P Point) Point {
+	cP, _ := *(*C.Point)(unsafe.Pointer(&P)), cgoAllocsUnknown
+	__ret := C.mirror_origin(cP)
+	__v := *(*Point)(unsafe.Pointer(&__ret))
+	return __v
+}
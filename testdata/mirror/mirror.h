@@ -0,0 +1,26 @@
+#ifndef MIRROR_H
+#define MIRROR_H
+
+typedef struct Point {
+    int x;
+    int y;
+} Point;
+
+// Record is mirrored by a Go struct with the same layout.
+typedef struct Record {
+    char tag;
+    double weight;
+    Point origin;
+    const char *name;
+    unsigned int flags : 3;
+    unsigned int kind : 5;
+    short id;
+    void (*notify)(int code);
+    unsigned char data[3];
+} Record;
+
+int mirror_update(Record *r, int delta);
+Record *mirror_find(const char *name);
+Point mirror_origin(Point p);
+
+#endif
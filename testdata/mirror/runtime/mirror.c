@@ -0,0 +1,30 @@
+#include <string.h>
+#include "mirror.h"
+
+static Record records[] = {
+	{.tag = 'a', .weight = 1.5, .origin = {1, 2}, .name = "first", .flags = 1, .kind = 3, .id = 1},
+	{.tag = 'b', .weight = 2.5, .origin = {3, 4}, .name = "second", .flags = 5, .kind = 17, .id = 2},
+};
+
+int mirror_update(Record *r, int delta) {
+	r->id += delta;
+	r->origin.x += delta;
+	r->flags = delta & 7;
+	r->data[2] = (unsigned char)r->tag;
+	return r->kind;
+}
+
+Record *mirror_find(const char *name) {
+	for (unsigned i = 0; i < sizeof(records) / sizeof(records[0]); i++) {
+		if (strcmp(records[i].name, name) == 0) {
+			return &records[i];
+		}
+	}
+	return NULL;
+}
+
+Point mirror_origin(Point p) {
+	p.x = -p.x;
+	p.y = -p.y;
+	return p;
+}
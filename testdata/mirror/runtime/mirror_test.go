@@ -0,0 +1,43 @@
+package mirror
+
+import "testing"
+
+func TestUpdate(t *testing.T) {
+	r := []Record{{Tag: 'x', Weight: 0.5, Origin: Point{X: 10, Y: 20}, Id: 5}}
+	r[0].SetKind(9)
+	if kind := Update(r, 3); kind != 9 {
+		t.Errorf("Update returned kind %d, want 9", kind)
+	}
+	if r[0].Id != 8 || r[0].Origin != (Point{X: 13, Y: 20}) {
+		t.Errorf("Update left id %d and origin %+v", r[0].Id, r[0].Origin)
+	}
+	if r[0].Flags() != 3 || r[0].Kind() != 9 {
+		t.Errorf("Update left flags %d and kind %d, want 3 and 9", r[0].Flags(), r[0].Kind())
+	}
+	if r[0].Data[2] != 'x' || r[0].Weight != 0.5 {
+		t.Errorf("Update left data %v and weight %v", r[0].Data, r[0].Weight)
+	}
+}
+
+func TestFind(t *testing.T) {
+	// the strings are passed as is without SafeStrings, so the names are terminated here
+	r := Find("second\x00")
+	if r == nil {
+		t.Fatal("Find returned nil")
+	}
+	if r.Tag != 'b' || r.Weight != 2.5 || r.Origin != (Point{X: 3, Y: 4}) || r.Id != 2 {
+		t.Errorf("Find returned %+v", *r)
+	}
+	if r.Flags() != 5 || r.Kind() != 17 {
+		t.Errorf("Find returned flags %d and kind %d, want 5 and 17", r.Flags(), r.Kind())
+	}
+	if r := Find("missing\x00"); r != nil {
+		t.Errorf("Find returned %+v for a missing record", *r)
+	}
+}
+
+func TestOrigin(t *testing.T) {
+	if p := Origin(Point{X: 1, Y: -2}); p != (Point{X: -1, Y: 2}) {
+		t.Errorf("Origin returned %+v", p)
+	}
+}
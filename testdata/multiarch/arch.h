@@ -0,0 +1,28 @@
+#ifndef ARCH_H
+#define ARCH_H
+
+#define ARCH_VERSION 3
+
+#if defined(__x86_64__) || defined(__aarch64__)
+#define ARCH_WORD_BITS 64
+#else
+#define ARCH_WORD_BITS 32
+#endif
+
+typedef long arch_word;
+
+typedef struct ArchRange {
+	long offset;
+	int flags;
+} ArchRange;
+
+typedef struct ArchBits {
+	unsigned short kind : 4;
+	unsigned short level : 12;
+} ArchBits;
+
+arch_word arch_align(arch_word value);
+
+int arch_version(void);
+
+#endif
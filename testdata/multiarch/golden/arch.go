@@ -0,0 +1,28 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package arch
+
+/*
+#include "arch.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+
+// Align function as declared in multiarch/arch.h:24
+func Align(Value Word) Word {
+	cValue, _ := (C.arch_word)(Value), cgoAllocsUnknown
+	__ret := C.arch_align(cValue)
+	__v := (Word)(__ret)
+	return __v
+}
+
+// Version function as declared in multiarch/arch.h:26
+func Version() int32 {
+	__ret := C.arch_version()
+	__v := (int32)(__ret)
+	return __v
+}
@@ -0,0 +1,100 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package arch
+
+/*
+#include "arch.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+// passRef returns the underlying C object, otherwise it will allocate one and set its values
+// from this wrapping struct, counting allocations into an allocation map.
+func (x *gArchRange) passRef() (*C.ArchRange, *cgoAllocMap) {
+	if x == nil {
+		return nil, nil
+	} else if x.refb08cc18 != nil {
+		if x.allocsb08cc18 != nil {
+			return x.refb08cc18, x.allocsb08cc18.(*cgoAllocMap)
+		} else {
+			return x.refb08cc18, nil
+		}
+	}
+	memb08cc18 := unsafe.Pointer(new(C.ArchRange))
+	refb08cc18 := (*C.ArchRange)(memb08cc18)
+	allocsb08cc18 := new(cgoAllocMap)
+	// allocsb08cc18.Add(memb08cc18)
+
+	var coffset_allocs *cgoAllocMap
+	refb08cc18.offset, coffset_allocs = (C.long)(x.gOffset), cgoAllocsUnknown
+	allocsb08cc18.Borrow(coffset_allocs)
+	x.gOffset = *new(int64)
+
+	var cflags_allocs *cgoAllocMap
+	refb08cc18.flags, cflags_allocs = (C.int)(x.gFlags), cgoAllocsUnknown
+	allocsb08cc18.Borrow(cflags_allocs)
+	x.gFlags = *new(int32)
+
+	x.refb08cc18 = refb08cc18
+	x.allocsb08cc18 = allocsb08cc18
+
+	return refb08cc18, allocsb08cc18
+}
+
+// NewArchRange new Go object and Mapping to C object.
+func NewArchRange(cOffset int64, cFlags int32) ArchRange {
+	obj := *new(gArchRange)
+	obj.gOffset = cOffset
+	obj.gFlags = cFlags
+
+	ret0, alloc0 := obj.passRef()
+
+	if len(alloc0.m) > 0 {
+		panic("Cgo memory alloced, please use func AllocArchRange.")
+	}
+	return *(*ArchRange)(unsafe.Pointer(ret0))
+}
+
+// AllocArchRange new Go object and Mapping to C object.
+func AllocArchRange(cOffset int64, cFlags int32) (*ArchRange, *cgoAllocMap) {
+	obj := *new(gArchRange)
+	obj.gOffset = cOffset
+	obj.gFlags = cFlags
+
+	ret0, alloc0 := obj.passRef()
+	ret1 := (*ArchRange)(unsafe.Pointer(ret0))
+	return ret1, alloc0
+}
+
+// Kind returns the value of kind bit-field.
+func (x *ArchBits) Kind() uint16 {
+	unit := (*uint8)(unsafe.Pointer(uintptr(unsafe.Pointer(x)) + 0))
+	return uint16((*unit >> 4) & 0xf)
+}
+
+// SetKind sets the value of kind bit-field.
+func (x *ArchBits) SetKind(v uint16) {
+	unit := (*uint8)(unsafe.Pointer(uintptr(unsafe.Pointer(x)) + 0))
+	bits := uint8(v)
+	*unit = *unit&^(0xf<<4) | (bits&0xf)<<4
+}
+
+// Level returns the value of level bit-field.
+func (x *ArchBits) Level() uint16 {
+	unit := (*uint16)(unsafe.Pointer(uintptr(unsafe.Pointer(x)) + 0))
+	return uint16((*unit >> 0) & 0xfff)
+}
+
+// SetLevel sets the value of level bit-field.
+func (x *ArchBits) SetLevel(v uint16) {
+	unit := (*uint16)(unsafe.Pointer(uintptr(unsafe.Pointer(x)) + 0))
+	bits := uint16(v)
+	*unit = *unit&^(0xfff<<0) | (bits&0xfff)<<0
+}
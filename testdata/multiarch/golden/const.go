@@ -0,0 +1,19 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package arch
+
+/*
+#include "arch.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+
+const (
+	// ARCH_VERSION as defined in multiarch/arch.h:4
+	// ARCH_VERSION as defined in multiarch/arch.h:4
+	ARCH_VERSION = 3
+)
@@ -0,0 +1,13 @@
+//go:build !(amd64 || 386 || arm || arm64 || s390x)
+// +build !amd64,!386,!arm,!arm64,!s390x
+
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package arch
+
+// The bindings are generated for amd64, 386, arm, arm64, s390x only, list the target
+// in the parser config and generate them again to build the package for it.
+var _ = bindingsNotGeneratedForThisTarget
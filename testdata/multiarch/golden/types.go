@@ -0,0 +1,50 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package arch
+
+/*
+#include "arch.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+import "unsafe"
+
+// ArchRange layout as computed for the target.
+const (
+	OffsetofArchRangeOffset = 0
+)
+
+// ArchBits as declared in multiarch/arch.h:22
+type gArchBits struct {
+	gKind          uint16
+	gLevel         uint16
+	ref2884610d    *C.ArchBits
+	allocs2884610d interface{}
+}
+
+type ArchBits struct {
+	_         [0]uint16
+	bitfield0 [2]byte
+}
+
+// ArchBits layout as computed for the target.
+const (
+	SizeofArchBits  = 2
+	AlignofArchBits = 2
+)
+
+// An out of bounds index or an overflow of uintptr reported by the compiler
+// here means that the layout of the type differs from the computed one.
+func _() {
+	var x [1]struct{}
+	// ArchRange
+	_ = x[SizeofArchRange-unsafe.Sizeof(C.ArchRange{})]
+	_ = x[OffsetofArchRangeOffset-unsafe.Offsetof(C.ArchRange{}.offset)]
+	_ = x[OffsetofArchRangeFlags-unsafe.Offsetof(C.ArchRange{}.flags)]
+	// ArchBits
+	_ = x[SizeofArchBits-unsafe.Sizeof(C.ArchBits{})]
+}
@@ -0,0 +1,36 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package arch
+
+/*
+#include "arch.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+
+// Word type as declared in multiarch/arch.h:12
+type Word int64
+
+// ArchRange as declared in multiarch/arch.h:17
+type gArchRange struct {
+	gOffset       int64
+	gFlags        int32
+	refb08cc18    *C.ArchRange
+	allocsb08cc18 interface{}
+}
+
+type ArchRange struct {
+	Offset int64
+	Flags  int32
+}
+
+// ArchRange layout as computed for the target.
+const (
+	SizeofArchRange        = 16
+	AlignofArchRange       = 8
+	OffsetofArchRangeFlags = 8
+)
@@ -0,0 +1,102 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package ownership
+
+/*
+#include "ownership.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+import (
+	"runtime"
+	"unsafe"
+)
+
+// buf_new allocates a buffer that is released with buf_destroy.
+//
+// New function as declared in ownership/ownership.h:7
+func New(Size int32) *OwnedBuffer {
+	cSize, _ := (C.int)(Size), cgoAllocsUnknown
+	__ret := C.buf_new(cSize)
+	__v := *(**Buffer)(unsafe.Pointer(&__ret))
+	return newOwnedBuffer(__v, func() {
+		C.buf_destroy((*C.Buffer)(unsafe.Pointer(__ret)))
+	})
+}
+
+// buf_describe returns a description allocated with malloc, the caller frees it.
+//
+// Describe function as declared in ownership/ownership.h:11
+func Describe(B *OwnedBuffer) string {
+	cB, _ := (*C.Buffer)(unsafe.Pointer(B.pointer())), cgoAllocsUnknown
+	__ret := C.buf_describe(cB)
+	__v := C.GoString((*C.char)(unsafe.Pointer(__ret)))
+	if __ret != nil {
+		C.free(unsafe.Pointer(__ret))
+	}
+	runtime.KeepAlive(B)
+	return __v
+}
+
+// buf_name returns the name owned by the buffer.
+//
+// Name function as declared in ownership/ownership.h:13
+func Name(B *OwnedBuffer) string {
+	cB, _ := (*C.Buffer)(unsafe.Pointer(B.pointer())), cgoAllocsUnknown
+	__ret := C.buf_name(cB)
+	__v := C.GoString((*C.char)(unsafe.Pointer(__ret)))
+	runtime.KeepAlive(B)
+	return __v
+}
+
+// buf_write copies the data into the buffer.
+//
+// Write function as declared in ownership/ownership.h:16
+func Write(B *OwnedBuffer, Data string, N int32) int32 {
+	cB, _ := (*C.Buffer)(unsafe.Pointer(B.pointer())), cgoAllocsUnknown
+	cData, cDataAllocMap := unpackPCharString(Data)
+	cN, _ := (C.int)(N), cgoAllocsUnknown
+	__ret := C.buf_write(cB, cData, cN)
+	cDataAllocMap.Free()
+	__v := (int32)(__ret)
+	runtime.KeepAlive(B)
+	return __v
+}
+
+// buf_set_label keeps the label until the buffer is destroyed.
+//
+// Set_label function as declared in ownership/ownership.h:18
+func Set_label(B *OwnedBuffer, Label string) {
+	cB, _ := (*C.Buffer)(unsafe.Pointer(B.pointer())), cgoAllocsUnknown
+	cLabel, _ := unpackMemoryPCharString(Label)
+	C.buf_set_label(cB, cLabel)
+	runtime.KeepAlive(B)
+}
+
+// buf_join writes the parts separated by spaces during the call.
+//
+// Join function as declared in ownership/ownership.h:20
+func Join(B *OwnedBuffer, Parts []string, N int32) int32 {
+	cB, _ := (*C.Buffer)(unsafe.Pointer(B.pointer())), cgoAllocsUnknown
+	cParts, cPartsAllocMap := unpackArgSString(Parts)
+	cN, _ := (C.int)(N), cgoAllocsUnknown
+	__ret := C.buf_join(cB, cParts, cN)
+	packSString(Parts, cParts)
+	cPartsAllocMap.Free()
+	__v := (int32)(__ret)
+	runtime.KeepAlive(B)
+	return __v
+}
+
+// buf_live returns the number of the buffers not destroyed yet.
+//
+// Live function as declared in ownership/ownership.h:22
+func Live() int32 {
+	__ret := C.buf_live()
+	__v := (int32)(__ret)
+	return __v
+}
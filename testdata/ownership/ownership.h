@@ -0,0 +1,24 @@
+#ifndef OWNERSHIP_H
+#define OWNERSHIP_H
+
+typedef struct Buffer Buffer;
+
+// buf_new allocates a buffer that is released with buf_destroy.
+Buffer *buf_new(int size);
+void buf_destroy(Buffer *b);
+
+// buf_describe returns a description allocated with malloc, the caller frees it.
+const char *buf_describe(const Buffer *b);
+// buf_name returns the name owned by the buffer.
+const char *buf_name(const Buffer *b);
+
+// buf_write copies the data into the buffer.
+int buf_write(Buffer *b, const char *data, int n);
+// buf_set_label keeps the label until the buffer is destroyed.
+void buf_set_label(Buffer *b, const char *label);
+// buf_join writes the parts separated by spaces during the call.
+int buf_join(Buffer *b, const char **parts, int n);
+// buf_live returns the number of the buffers not destroyed yet.
+int buf_live(void);
+
+#endif
@@ -0,0 +1,70 @@
+#include <stdio.h>
+#include <stdlib.h>
+#include <string.h>
+#include "ownership.h"
+
+struct Buffer {
+	char name[16];
+	char *data;
+	int size;
+	int len;
+	char *label;
+};
+
+static int live;
+
+Buffer *buf_new(int size) {
+	Buffer *b = calloc(1, sizeof(Buffer));
+	b->data = calloc(size, 1);
+	b->size = size;
+	snprintf(b->name, sizeof(b->name), "buf%d", size);
+	live++;
+	return b;
+}
+
+void buf_destroy(Buffer *b) {
+	free(b->data);
+	free(b->label);
+	memset(b, 0, sizeof(Buffer));
+	free(b);
+	live--;
+}
+
+const char *buf_describe(const Buffer *b) {
+	char *s = malloc(64);
+	snprintf(s, 64, "%s %d/%d", b->name, b->len, b->size);
+	return s;
+}
+
+const char *buf_name(const Buffer *b) {
+	return b->name;
+}
+
+int buf_write(Buffer *b, const char *data, int n) {
+	if (n > b->size - b->len) {
+		n = b->size - b->len;
+	}
+	memcpy(b->data + b->len, data, n);
+	b->len += n;
+	return n;
+}
+
+void buf_set_label(Buffer *b, const char *label) {
+	free(b->label);
+	b->label = (char *)label;
+}
+
+int buf_join(Buffer *b, const char **parts, int n) {
+	int written = 0;
+	for (int i = 0; i < n; i++) {
+		if (i > 0) {
+			written += buf_write(b, " ", 1);
+		}
+		written += buf_write(b, parts[i], strlen(parts[i]));
+	}
+	return written;
+}
+
+int buf_live(void) {
+	return live;
+}
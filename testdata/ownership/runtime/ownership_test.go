@@ -0,0 +1,46 @@
+package ownership
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestFree(t *testing.T) {
+	b := New(16)
+	if n := Live(); n != 1 {
+		t.Fatalf("%d buffers are live after New, want 1", n)
+	}
+	if n := Write(b, "hello", 5); n != 5 {
+		t.Errorf("Write returned %d, want 5", n)
+	}
+	if got := Describe(b); got != "buf16 5/16" {
+		t.Errorf("Describe returned %q", got)
+	}
+	name := Name(b)
+	if name != "buf16" {
+		t.Errorf("Name returned %q", name)
+	}
+	b.Free()
+	b.Free()
+	if n := Live(); n != 0 {
+		t.Fatalf("%d buffers are live after Free, want 0", n)
+	}
+	// the borrowed name is a copy, so it outlives the buffer
+	if name != "buf16" {
+		t.Errorf("Name returned %q after Free", name)
+	}
+}
+
+func TestFinalizer(t *testing.T) {
+	for i := 0; i < 8; i++ {
+		Set_label(New(8), "label")
+	}
+	for deadline := time.Now().Add(5 * time.Second); Live() > 0; {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d buffers are still live after GC", Live())
+		}
+		runtime.GC()
+		time.Sleep(10 * time.Millisecond)
+	}
+}
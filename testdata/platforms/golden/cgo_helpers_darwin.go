@@ -0,0 +1,74 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package plat
+
+/*
+#include "plat.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+import (
+	"unsafe"
+)
+
+// passRef returns the underlying C object, otherwise it will allocate one and set its values
+// from this wrapping struct, counting allocations into an allocation map.
+func (x *gPlatFile) passRef() (*C.PlatFile, *cgoAllocMap) {
+	if x == nil {
+		return nil, nil
+	} else if x.refbe01d6fc != nil {
+		if x.allocsbe01d6fc != nil {
+			return x.refbe01d6fc, x.allocsbe01d6fc.(*cgoAllocMap)
+		} else {
+			return x.refbe01d6fc, nil
+		}
+	}
+	membe01d6fc := unsafe.Pointer(new(C.PlatFile))
+	refbe01d6fc := (*C.PlatFile)(membe01d6fc)
+	allocsbe01d6fc := new(cgoAllocMap)
+	// allocsbe01d6fc.Add(membe01d6fc)
+
+	var chandle_allocs *cgoAllocMap
+	refbe01d6fc.handle, chandle_allocs = (C.plat_handle)(x.gHandle), cgoAllocsUnknown
+	allocsbe01d6fc.Borrow(chandle_allocs)
+	x.gHandle = *new(Handle)
+
+	var csize_allocs *cgoAllocMap
+	refbe01d6fc.size, csize_allocs = (C.long)(x.gSize), cgoAllocsUnknown
+	allocsbe01d6fc.Borrow(csize_allocs)
+	x.gSize = *new(int64)
+
+	x.refbe01d6fc = refbe01d6fc
+	x.allocsbe01d6fc = allocsbe01d6fc
+
+	return refbe01d6fc, allocsbe01d6fc
+}
+
+// NewPlatFile new Go object and Mapping to C object.
+func NewPlatFile(cHandle Handle, cSize int64) PlatFile {
+	obj := *new(gPlatFile)
+	obj.gHandle = cHandle
+	obj.gSize = cSize
+
+	ret0, alloc0 := obj.passRef()
+
+	if len(alloc0.m) > 0 {
+		panic("Cgo memory alloced, please use func AllocPlatFile.")
+	}
+	return *(*PlatFile)(unsafe.Pointer(ret0))
+}
+
+// AllocPlatFile new Go object and Mapping to C object.
+func AllocPlatFile(cHandle Handle, cSize int64) (*PlatFile, *cgoAllocMap) {
+	obj := *new(gPlatFile)
+	obj.gHandle = cHandle
+	obj.gSize = cSize
+
+	ret0, alloc0 := obj.passRef()
+	ret1 := (*PlatFile)(unsafe.Pointer(ret0))
+	return ret1, alloc0
+}
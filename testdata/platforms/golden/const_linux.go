@@ -0,0 +1,19 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package plat
+
+/*
+#include "plat.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+
+const (
+	// PLAT_PATH_SEPARATOR as defined in platforms/plat.h:11
+	// PLAT_PATH_SEPARATOR as defined in platforms/plat.h:11
+	PLAT_PATH_SEPARATOR = '/'
+)
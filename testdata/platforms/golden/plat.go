@@ -0,0 +1,23 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package plat
+
+/*
+#include "plat.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+
+// Open function as declared in platforms/plat.h:19
+func Open(Path string, File []PlatFile) int32 {
+	cPath, _ := unpackPCharString(Path)
+	cFile, _ := unpackArgSPlatFile(File)
+	__ret := C.plat_open(cPath, cFile)
+	packSPlatFile(File, cFile)
+	__v := (int32)(__ret)
+	return __v
+}
@@ -0,0 +1,23 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package plat
+
+/*
+#include "plat.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+
+// Open_bundle function as declared in platforms/plat.h:22
+func Open_bundle(Bundle_id string, File []PlatFile) int32 {
+	cBundle_id, _ := unpackPCharString(Bundle_id)
+	cFile, _ := unpackArgSPlatFile(File)
+	__ret := C.plat_open_bundle(cBundle_id, cFile)
+	packSPlatFile(File, cFile)
+	__v := (int32)(__ret)
+	return __v
+}
@@ -0,0 +1,13 @@
+//go:build !(linux || darwin || windows)
+// +build !linux,!darwin,!windows
+
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package plat
+
+// The bindings are generated for linux, darwin, windows only, list the target
+// in the parser config and generate them again to build the package for it.
+var _ = bindingsNotGeneratedForThisTarget
@@ -0,0 +1,29 @@
+// THE AUTOGENERATED LICENSE. ALL THE RIGHTS ARE RESERVED BY ROBOTS.
+
+// WARNING: This file has automatically been generated
+// Code generated by https://git.io/c-for-go. DO NOT EDIT.
+
+package plat
+
+/*
+#include "plat.h"
+#include <stdlib.h>
+#include "cgo_helpers.h"
+*/
+import "C"
+
+// PlatFile as declared in platforms/plat.h:17
+type gPlatFile struct {
+	gHandle        Handle
+	gSize          int64
+	refbe01d6fc    *C.PlatFile
+	allocsbe01d6fc interface{}
+}
+
+type PlatFile struct {
+	Handle Handle
+	Size   int64
+}
+
+// Handle type as declared in darwin/plat_types.h:1
+type Handle int32
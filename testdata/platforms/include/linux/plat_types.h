@@ -0,0 +1 @@
+typedef int plat_handle;
@@ -0,0 +1 @@
+typedef void *plat_handle;
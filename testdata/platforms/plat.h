@@ -0,0 +1,25 @@
+#ifndef PLAT_H
+#define PLAT_H
+
+#include <plat_types.h>
+
+#define PLAT_VERSION 2
+
+#if defined(_WIN32)
+#define PLAT_PATH_SEPARATOR '\\'
+#else
+#define PLAT_PATH_SEPARATOR '/'
+#endif
+
+typedef struct PlatFile {
+	plat_handle handle;
+	long size;
+} PlatFile;
+
+int plat_open(const char *path, PlatFile *file);
+
+#ifdef __APPLE__
+int plat_open_bundle(const char *bundle_id, PlatFile *file);
+#endif
+
+#endif
@@ -26,6 +26,7 @@ func (t *Translator) walkExternalDeclaration(d *cc.ExternalDeclaration) {
 		var decl *CDecl
 		if declr := d.FunctionDefinition.Declarator; declr != nil {
 			decl = t.declarator(declr)
+			decl.Doc = t.docAt(d.Pos())
 			t.registerTagsOf(decl)
 		} else {
 			return
@@ -57,6 +58,7 @@ func (t *Translator) walkDeclaration(d *cc.Declaration) (declared []*CDecl) {
 		list := d.InitDeclaratorListOpt.InitDeclaratorList
 		for list != nil {
 			decl := t.declarator(list.InitDeclarator.Declarator)
+			decl.Doc = t.docAt(d.Pos())
 			init := list.InitDeclarator.Initializer
 			if init != nil && init.Expression != nil {
 				decl.Value = init.Expression.Value
@@ -72,6 +74,7 @@ func (t *Translator) walkDeclaration(d *cc.Declaration) (declared []*CDecl) {
 		}
 	} else if declr := d.Declarator(); declr != nil {
 		decl := t.declarator(declr)
+		decl.Doc = t.docAt(d.Pos())
 		t.registerTagsOf(decl)
 		declared = append(declared, decl)
 	}
@@ -117,6 +120,7 @@ func (t *Translator) enumSpec(base *CTypeSpec, typ cc.Type) *CEnumSpec {
 		m := &CDecl{
 			Name: name,
 			Pos:  en.DefTok.Pos(),
+			Doc:  t.docAt(en.DefTok.Pos()),
 		}
 		switch {
 		case en.Value == nil:
@@ -223,24 +227,181 @@ func (t *Translator) structSpec(base *CTypeSpec, typ cc.Type, deep int) *CStruct
 		OuterArr: base.OuterArr,
 		InnerArr: base.InnerArr,
 	}
+	if size := typ.SizeOf(); size > 0 {
+		spec.Size = size
+		spec.Align = typ.AlignOf()
+	}
 	if deep > maxDeepLevel {
 		return spec
 	}
 	members, _ := typ.Members()
+	// the layout of cc is not reliable for the bit-fields, the structs having
+	// any are laid out again including the unnamed bit-fields
+	fields := make([]layoutField, 0, len(members))
+	var relayout bool
 	for i, m := range members {
+		size, align, abi := t.memberLayout(m.Type)
+		field := layoutField{
+			size:  size,
+			align: align,
+			bits:  m.Bits,
+		}
+		if m.Bits > 0 || (m.Declarator == nil && m.Name == 0) || abi {
+			relayout = true
+		}
+		if m.Declarator == nil && m.Name == 0 {
+			// unnamed bit-fields are used only for padding
+			fields = append(fields, field)
+			continue
+		}
 		var pos token.Pos
 		if m.Declarator != nil {
 			pos = m.Declarator.Pos()
 		}
-		spec.Members = append(spec.Members, &CDecl{
-			Name: memberName(i, m),
-			Spec: t.typeSpec(m.Type, deep+1, false),
-			Pos:  pos,
-		})
+		decl := &CDecl{
+			Name:   memberName(i, m),
+			Spec:   t.typeSpec(m.Type, deep+1, false),
+			Pos:    pos,
+			Offset: m.OffsetOf,
+			Size:   size,
+			Align:  align,
+			Doc:    t.docAt(pos),
+		}
+		if nested, ok := decl.Spec.(*CStructSpec); ok && nested.Pointers == 0 &&
+			len(nested.OuterArr) == 0 && nested.Size > 0 && nested.Size != decl.Size {
+			// a nested struct that has been laid out again
+			decl.Size, decl.Align = nested.Size, nested.Align
+			field.size, field.align = nested.Size, nested.Align
+			relayout = true
+		}
+		if m.Bits > 0 {
+			decl.BitWidth = m.Bits
+		}
+		field.decl = decl
+		fields = append(fields, field)
+		spec.Members = append(spec.Members, decl)
+	}
+	if relayout && spec.Size > 0 {
+		spec.Size, spec.Align = layoutFields(fields, spec.IsUnion, t.bigEndian)
+	}
+	if spec.Size > 0 {
+		setPadding(spec)
 	}
 	return spec
 }
 
+// memberLayout returns the size and the alignment of a struct member, abi is set if they
+// differ from the ones of cc because the type has a size that cc can't model.
+func (t *Translator) memberLayout(typ cc.Type) (size, align int, abi bool) {
+	elem, n := typ, 1
+	for elem.Kind() == cc.Array && elem.Elements() > 0 {
+		n *= elem.Elements()
+		elem = elem.Element()
+	}
+	if item, ok := t.abiItems[elem.Kind()]; ok {
+		return n * item.Size, item.StructAlign, true
+	}
+	return typ.SizeOf(), typ.StructAlignOf(), false
+}
+
+// layoutField is a struct member as seen by layoutFields, decl is nil for the unnamed bit-fields.
+type layoutField struct {
+	decl  *CDecl
+	size  int
+	align int
+	bits  int
+}
+
+// layoutFields lays out the members the way the System V ABI does and returns the size
+// and the alignment of the struct. A bit-field follows the previous member unless it would
+// span more units of the alignment of its type than the type has, then it starts at the next
+// unit; the named bit-fields align the struct as their type does. The storage unit of a
+// bit-field is the smallest aligned one that holds its bits, up to the size of its type.
+func layoutFields(fields []layoutField, isUnion, bigEndian bool) (size, align int) {
+	var bitPos, maxBits int
+	align = 1
+	for _, f := range fields {
+		if isUnion {
+			bitPos = 0
+		}
+		unitBits := f.align * 8
+		switch {
+		case f.bits == 0 && f.decl == nil:
+			// a zero-width bit-field starts the next unit
+			bitPos = roundUp(bitPos, unitBits)
+		case f.bits == 0:
+			bitPos = roundUp(bitPos, unitBits)
+			f.decl.Offset = bitPos / 8
+			bitPos += f.size * 8
+		default:
+			if (bitPos%unitBits+f.bits+unitBits-1)/unitBits > f.size/f.align {
+				bitPos = roundUp(bitPos, unitBits)
+			}
+			if f.decl != nil {
+				setBitUnit(f.decl, bitPos, f.size, bigEndian)
+			}
+			bitPos += f.bits
+		}
+		if f.decl != nil && f.align > align {
+			align = f.align
+		}
+		if bitPos > maxBits {
+			maxBits = bitPos
+		}
+	}
+	return roundUp((maxBits+7)/8, align), align
+}
+
+// setBitUnit sets the storage unit of the bit-field that starts at the bit position
+// in the allocation order, typeSize is the size of its declared type.
+func setBitUnit(decl *CDecl, bitPos, typeSize int, bigEndian bool) {
+	decl.BitPos = bitPos
+	unit := 1
+	for unit < typeSize && bitPos/(unit*8) != (bitPos+decl.BitWidth-1)/(unit*8) {
+		unit *= 2
+	}
+	decl.BitUnit = unit
+	decl.Offset = bitPos / (unit * 8) * unit
+	decl.BitOffset = bitPos - decl.Offset*8
+	if bigEndian {
+		// the bits are allocated from the most significant one of the storage unit
+		decl.BitOffset = unit*8 - decl.BitOffset - decl.BitWidth
+	}
+}
+
+func roundUp(n, align int) int {
+	if align <= 1 {
+		return n
+	}
+	return (n + align - 1) / align * align
+}
+
+// setPadding computes the padding before each member of the struct and after the last one,
+// the bit-fields that share a storage unit have no padding between them.
+func setPadding(spec *CStructSpec) {
+	var end int
+	for _, m := range spec.Members {
+		size := m.Size
+		if m.IsBitField() {
+			size = m.BitUnit
+		}
+		switch {
+		case spec.IsUnion:
+			if size > end {
+				end = size
+			}
+		case m.Offset >= end:
+			m.Padding = m.Offset - end
+			end = m.Offset + size
+		case m.Offset+size > end:
+			end = m.Offset + size
+		}
+	}
+	if spec.Size > end {
+		spec.TailPadding = spec.Size - end
+	}
+}
+
 func (t *Translator) functionSpec(base *CTypeSpec, typ cc.Type, deep int) *CFunctionSpec {
 	spec := &CFunctionSpec{
 		Pointers: base.Pointers,
@@ -256,7 +417,8 @@ func (t *Translator) functionSpec(base *CTypeSpec, typ cc.Type, deep int) *CFunc
 	if ret := typ.Result(); ret != nil && ret.Kind() != cc.Void {
 		spec.Return = t.typeSpec(ret, deep+1, true)
 	}
-	params, _ := typ.Parameters()
+	params, isVariadic := typ.Parameters()
+	spec.IsVariadic = isVariadic
 	for i, p := range params {
 		spec.Params = append(spec.Params, &CDecl{
 			Name: paramName(i, p),
@@ -366,14 +528,9 @@ func (t *Translator) typeSpec(typ cc.Type, deep int, isRet bool) CType {
 		}
 		return s
 	case cc.Union:
-		return &CStructSpec{
-			Tag:      t.getStructTag(typ),
-			IsUnion:  true,
-			Pointers: spec.Pointers,
-			OuterArr: spec.OuterArr,
-			InnerArr: spec.InnerArr,
-			Typedef:  typedefNameOf(typ),
-		}
+		s := t.structSpec(spec, typ, deep+1)
+		s.Typedef = typedefNameOf(typ)
+		return s
 	case cc.Struct:
 		s := t.structSpec(spec, typ, deep+1)
 		if !isRet {
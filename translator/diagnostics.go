@@ -0,0 +1,112 @@
+package translator
+
+import (
+	"fmt"
+	"go/token"
+	"io"
+	"sort"
+	"sync"
+
+	"modernc.org/xc"
+)
+
+type DiagnosticKind string
+
+const (
+	// DiagSkipped means the declaration has been left out of the bindings.
+	DiagSkipped DiagnosticKind = "skipped"
+	// DiagFiltered means the declaration has been rejected by the config rules.
+	DiagFiltered DiagnosticKind = "filtered"
+	// DiagDegraded means the declaration has been translated using a fallback.
+	DiagDegraded DiagnosticKind = "degraded"
+)
+
+// Diagnostic records a declaration that has been skipped or degraded on its way
+// to the bindings, along with the reason.
+type Diagnostic struct {
+	Kind   DiagnosticKind `json:"kind"`
+	Name   string         `json:"name"`
+	File   string         `json:"file,omitempty"`
+	Line   int            `json:"line,omitempty"`
+	Reason string         `json:"reason"`
+}
+
+func (d Diagnostic) String() string {
+	if len(d.File) > 0 {
+		return fmt.Sprintf("%s:%d: %s %s: %s", narrowPath(d.File), d.Line, d.Kind, d.Name, d.Reason)
+	}
+	return fmt.Sprintf("%s %s: %s", d.Kind, d.Name, d.Reason)
+}
+
+// Diagnostics collects the diagnostics reported by the translator and the generator,
+// it's safe for concurrent use. The duplicate reports are dropped.
+type Diagnostics struct {
+	mux  sync.Mutex
+	seen map[Diagnostic]bool
+	list []Diagnostic
+}
+
+func NewDiagnostics() *Diagnostics {
+	return &Diagnostics{
+		seen: make(map[Diagnostic]bool),
+	}
+}
+
+// Add records a diagnostic for the named declaration at pos, pos may be token.NoPos.
+func (d *Diagnostics) Add(kind DiagnosticKind, pos token.Pos, name, reason string) {
+	diag := Diagnostic{
+		Kind:   kind,
+		Name:   name,
+		Reason: reason,
+	}
+	if pos.IsValid() {
+		position := xc.FileSet.Position(pos)
+		diag.File = position.Filename
+		diag.Line = position.Line
+	}
+	d.Report(diag)
+}
+
+// Report records the diagnostic as is.
+func (d *Diagnostics) Report(diag Diagnostic) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if d.seen[diag] {
+		return
+	}
+	d.seen[diag] = true
+	d.list = append(d.list, diag)
+}
+
+// List returns the diagnostics ordered by their location.
+func (d *Diagnostics) List() []Diagnostic {
+	d.mux.Lock()
+	list := make([]Diagnostic, len(d.list))
+	copy(list, d.list)
+	d.mux.Unlock()
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].File != list[j].File {
+			return list[i].File < list[j].File
+		} else if list[i].Line != list[j].Line {
+			return list[i].Line < list[j].Line
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// WriteSummary writes a human-readable summary of the diagnostics. The filtered
+// declarations are only counted, as they are usually rejected on purpose.
+func WriteSummary(wr io.Writer, list []Diagnostic) {
+	var filtered int
+	for _, diag := range list {
+		if diag.Kind == DiagFiltered {
+			filtered++
+			continue
+		}
+		fmt.Fprintln(wr, diag)
+	}
+	if filtered > 0 {
+		fmt.Fprintf(wr, "%d declaration(s) filtered by the rules\n", filtered)
+	}
+}
@@ -0,0 +1,75 @@
+package translator
+
+import (
+	"go/token"
+	"io/ioutil"
+	"strings"
+
+	"modernc.org/xc"
+)
+
+// docAt returns the text of the comment that ends right above the line of the given position,
+// the comment markers are stripped. Comments that trail some code are not considered docs.
+func (t *Translator) docAt(p token.Pos) string {
+	if !p.IsValid() {
+		return ""
+	}
+	pos := xc.FileSet.Position(p)
+	lines := t.sourceLines(pos.Filename)
+	if pos.Line < 2 || pos.Line > len(lines)+1 {
+		return ""
+	}
+	var doc []string
+	end := pos.Line - 2
+	switch last := strings.TrimSpace(lines[end]); {
+	case strings.HasPrefix(last, "//"):
+		start := end
+		for start > 0 && strings.HasPrefix(strings.TrimSpace(lines[start-1]), "//") {
+			start--
+		}
+		for _, line := range lines[start : end+1] {
+			line = strings.TrimSpace(line)
+			line = strings.TrimLeft(line, "/!")
+			doc = append(doc, strings.TrimPrefix(line, " "))
+		}
+	case strings.HasSuffix(last, "*/"):
+		start := end
+		for start > 0 && !strings.Contains(lines[start], "/*") {
+			start--
+		}
+		first := strings.TrimSpace(lines[start])
+		if !strings.HasPrefix(first, "/*") {
+			// a comment after some code
+			return ""
+		}
+		for i, line := range lines[start : end+1] {
+			line = strings.TrimSpace(line)
+			if i == 0 {
+				line = strings.TrimLeft(strings.TrimPrefix(line, "/*"), "*!<")
+			}
+			if i == end-start {
+				line = strings.TrimSuffix(line, "*/")
+			}
+			if i > 0 && strings.HasPrefix(line, "*") {
+				line = strings.TrimLeft(line, "*")
+			}
+			doc = append(doc, strings.TrimSpace(line))
+		}
+	default:
+		return ""
+	}
+	return strings.TrimSpace(strings.Join(doc, "\n"))
+}
+
+// sourceLines reads the source file once and keeps its lines for further lookups.
+func (t *Translator) sourceLines(path string) []string {
+	if lines, ok := t.sources[path]; ok {
+		return lines
+	}
+	var lines []string
+	if data, err := ioutil.ReadFile(path); err == nil {
+		lines = strings.Split(string(data), "\n")
+	}
+	t.sources[path] = lines
+	return lines
+}
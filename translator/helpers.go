@@ -73,11 +73,18 @@ func (t *Translator) IsTokenIgnored(p token.Pos) bool {
 	return false
 }
 
+// Location returns the plain file:line location of p, with the path narrowed
+// to the file name and its parent dir.
+func (t *Translator) Location(p token.Pos) string {
+	pos := xc.FileSet.Position(p)
+	return fmt.Sprintf("%s:%d", narrowPath(pos.Filename), pos.Line)
+}
+
 func (t *Translator) SrcLocation(docTarget RuleTarget, name string, p token.Pos) string {
 	pos := xc.FileSet.Position(p)
 	filename := filepath.Base(pos.Filename)
 	defaultLocation := func() string {
-		return fmt.Sprintf("%s:%d", narrowPath(pos.Filename), pos.Line)
+		return t.Location(p)
 	}
 	rxs, ok := t.compiledRxs[ActionDocument][docTarget]
 	if !ok {
@@ -321,3 +328,22 @@ func readNumeric(v []rune) string {
 	}
 	return wrap()
 }
+
+// readUntypedNumeric reads numeric part of the value without the type suffixes,
+// ex: 1000UL being read as 1000, so the value can take the type of an expression.
+func readUntypedNumeric(v []rune) string {
+	result := readNumeric(v)
+	if i := strings.IndexByte(result, '('); i > 0 && strings.HasSuffix(result, ")") {
+		return result[i+1 : len(result)-1]
+	}
+	return result
+}
+
+// isFloating checks if the numeric value is a floating point one, i.e. 1.5f or 1e-3.
+func isFloating(v []rune) bool {
+	str := strings.ToLower(string(v))
+	if strings.HasPrefix(str, "0x") {
+		return strings.ContainsAny(str, ".p")
+	}
+	return strings.ContainsAny(str, ".e")
+}
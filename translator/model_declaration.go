@@ -55,6 +55,28 @@ type CDecl struct {
 	IsDefine   bool
 	Pos        token.Pos
 	Src        string
+	// Doc is the comment found right above the declaration in C header.
+	Doc string
+	// Offset is the byte offset of a struct member, for bit-fields
+	// it points to the storage unit that holds the bits.
+	Offset int
+	// Size and Align are the byte size and the alignment of a struct member.
+	Size  int
+	Align int
+	// Padding is the number of bytes between the end of the previous member and a struct member.
+	Padding int
+	// BitWidth, BitOffset and BitUnit describe a bit-field member: its width and offset
+	// in bits from the least significant bit of a storage unit of BitUnit bytes.
+	BitWidth  int
+	BitOffset int
+	BitUnit   int
+	// BitPos is the position of the first bit of a bit-field from the start of the struct
+	// in the allocation order, the bits are held by the bytes from BitPos/8 on.
+	BitPos int
+}
+
+func (c CDecl) IsBitField() bool {
+	return c.BitWidth > 0
 }
 
 func (c CDecl) String() string {
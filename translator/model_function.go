@@ -11,6 +11,9 @@ type CFunctionSpec struct {
 	Return   CType
 	Params   []*CDecl
 	Pointers uint8
+	// IsVariadic is set for functions that take a variable number of arguments,
+	// those can't be called from cgo directly.
+	IsVariadic bool
 }
 
 func (c CFunctionSpec) String() string {
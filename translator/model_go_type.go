@@ -26,7 +26,7 @@ func (spec *GoTypeSpec) splitPointers(ptrTip Tip, n uint8) {
 	case TipPtrRef:
 		spec.Slices = spec.Slices + n - 1
 		spec.Pointers++
-	case TipPtrSRef, TipPtrInst:
+	case TipPtrSRef, TipPtrInst, TipPtrHandle:
 		spec.Pointers += n
 	case TipPtrArr:
 		spec.Slices += n
@@ -14,6 +14,12 @@ type CStructSpec struct {
 	Pointers uint8
 	InnerArr ArraySpec
 	OuterArr ArraySpec
+	// Size and Align are the byte size and the alignment of a complete struct or union
+	// as laid out by the data model of the target.
+	Size  int
+	Align int
+	// TailPadding is the number of bytes between the end of the last member and the end of the struct.
+	TailPadding int
 }
 
 func (spec CStructSpec) String() string {
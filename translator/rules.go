@@ -1,10 +1,13 @@
 package translator
 
+import "strings"
+
 type Rules map[RuleTarget][]RuleSpec
 type ConstRules map[ConstScope]ConstRule
 type PtrTips map[TipScope][]TipSpec
 type TypeTips map[TipScope][]TipSpec
 type MemTips []TipSpec
+type ConstTypes []ConstTypeSpec
 
 type RuleSpec struct {
 	From, To  string
@@ -73,6 +76,9 @@ const (
 	ConstCGOAlias ConstRule = "cgo"
 	ConstExpand   ConstRule = "expand"
 	ConstEval     ConstRule = "eval"
+	// ConstTyped expands the defines like ConstExpand does and assigns Go types to them,
+	// see Translator.typeDefines.
+	ConstTyped ConstRule = "typed"
 )
 
 type ConstScope string
@@ -81,8 +87,16 @@ const (
 	ConstEnum    ConstScope = "enum"
 	ConstDecl    ConstScope = "decl"
 	ConstDefines ConstScope = "defines"
+	ConstMacros  ConstScope = "macros"
 )
 
+// ConstTypeSpec assigns a C type name like "unsigned int" or a typedef name
+// to the defines and function-like macros matching the Target regexp.
+type ConstTypeSpec struct {
+	Target string
+	Type   string
+}
+
 type Tip string
 
 const (
@@ -90,7 +104,12 @@ const (
 	TipPtrRef    Tip = "ref"
 	TipPtrArr    Tip = "arr"
 	TipPtrInst   Tip = "inst"
+	TipPtrHandle Tip = "handle"
 	TipMemRaw    Tip = "raw"
+	TipMemMirror Tip = "mirror"
+	TipOwn       Tip = "own"
+	TipBorrow    Tip = "borrow"
+	TipTransfer  Tip = "transfer"
 	TipTypeNamed Tip = "named"
 	TipTypePlain Tip = "plain"
 	NoTip        Tip = ""
@@ -103,34 +122,60 @@ const (
 	TipKindPtr     TipKind = "ptr"
 	TipKindType    TipKind = "type"
 	TipKindMem     TipKind = "mem"
+	TipKindOwn     TipKind = "own"
 )
 
+// TipFreeWithPrefix starts the ownership tips naming the C function that frees
+// the memory, e.g. free-with:foo_destroy.
+const TipFreeWithPrefix = "free-with:"
+
 func (t Tip) Kind() TipKind {
 	switch t {
-	case TipPtrArr, TipPtrRef, TipPtrSRef, TipPtrInst:
+	case TipPtrArr, TipPtrRef, TipPtrSRef, TipPtrInst, TipPtrHandle:
 		return TipKindPtr
 	case TipTypePlain, TipTypeNamed:
 		return TipKindType
-	case TipMemRaw:
+	case TipMemRaw, TipMemMirror:
 		return TipKindMem
+	case TipOwn, TipBorrow, TipTransfer:
+		return TipKindOwn
 	default:
+		if len(t.FreeWith()) > 0 {
+			return TipKindOwn
+		}
 		return TipKindUnknown
 	}
 }
 
 func (t Tip) IsValid() bool {
 	switch t {
-	case TipPtrArr, TipPtrRef, TipPtrSRef, TipPtrInst:
+	case TipPtrArr, TipPtrRef, TipPtrSRef, TipPtrInst, TipPtrHandle:
 		return true
 	case TipTypePlain, TipTypeNamed:
 		return true
-	case TipMemRaw:
+	case TipMemRaw, TipMemMirror:
+		return true
+	case TipOwn, TipBorrow, TipTransfer:
 		return true
 	default:
-		return false
+		return len(t.FreeWith()) > 0
 	}
 }
 
+// FreeWith returns the name of the C function set by the free-with tip.
+func (t Tip) FreeWith() string {
+	if strings.HasPrefix(string(t), TipFreeWithPrefix) {
+		return strings.TrimSpace(strings.TrimPrefix(string(t), TipFreeWithPrefix))
+	}
+	return ""
+}
+
+// HasCLayout reports whether the Go type of a struct with the mem tip has the layout
+// of the C type, so it's passed to C by a pointer cast without copying.
+func (t Tip) HasCLayout() bool {
+	return t == TipMemRaw || t == TipMemMirror
+}
+
 type TipSpec struct {
 	Target  string
 	Tips    Tips
@@ -2,7 +2,9 @@ package translator
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"go/token"
 	"regexp"
 	"sort"
 	"strings"
@@ -18,16 +20,24 @@ type Translator struct {
 	compiledPtrTipRxs  PtrTipRxMap
 	compiledTypeTipRxs TypeTipRxMap
 	compiledMemTipRxs  MemTipRxList
+	compiledConstTypes []ConstTypeRx
 	constRules         ConstRules
 	typemap            CTypeMap
+	builtinTypemap     CTypeMap
+	pointerSize        int
+	bigEndian          bool
 	fileScope          *cc.Bindings
 	ignoredFiles       map[string]struct{}
+	// abiItems are the sizes of the types that cc can't model, the structs are laid out with them.
+	abiItems map[cc.Kind]cc.ModelItem
 
 	valueMap map[string]Value
 	exprMap  map[string]string
 	tagMap   map[string]*CDecl
+	sources  map[string][]string
 
 	defines  []*CDecl
+	macros   []*CDecl
 	typedefs []*CDecl
 	declares []*CDecl
 
@@ -38,6 +48,8 @@ type Translator struct {
 	ptrTipCache  *TipCache
 	typeTipCache *TipCache
 	memTipCache  *TipCache
+
+	diagnostics *Diagnostics
 }
 
 type RxMap map[RuleTarget][]Rx
@@ -53,6 +65,11 @@ type PtrTipRxMap map[TipScope][]TipSpecRx
 type TypeTipRxMap map[TipScope][]TipSpecRx
 type MemTipRxList []TipSpecRx
 
+type ConstTypeRx struct {
+	Target *regexp.Regexp
+	Type   string
+}
+
 type TipSpecRx struct {
 	Target  *regexp.Regexp
 	Default Tip
@@ -62,17 +79,47 @@ type TipSpecRx struct {
 
 func (t TipSpecRx) TipAt(i int) Tip {
 	if i < len(t.tips) {
-		if tip := t.tips[i]; tip.IsValid() {
+		if tip := t.tips[i]; tip.IsValid() && tip.Kind() != TipKindOwn {
 			return tip
 		}
 	}
-	return t.Default
+	return t.defaultTip()
 }
 
 func (t TipSpecRx) Self() Tip {
-	if t.self.IsValid() {
+	if t.self.IsValid() && t.self.Kind() != TipKindOwn {
+		return t.self
+	}
+	return t.defaultTip()
+}
+
+// OwnTipAt returns the ownership tip of the parameter at i, the ownership tips
+// are listed along with the ptr or mem tips and are not returned by TipAt.
+func (t TipSpecRx) OwnTipAt(i int) Tip {
+	if i < len(t.tips) && t.tips[i].Kind() == TipKindOwn {
+		return t.tips[i]
+	}
+	if t.Default.Kind() == TipKindOwn {
+		return t.Default
+	}
+	return NoTip
+}
+
+// OwnSelf returns the ownership tip of the return value.
+func (t TipSpecRx) OwnSelf() Tip {
+	if t.self.Kind() == TipKindOwn {
 		return t.self
 	}
+	if t.Default.Kind() == TipKindOwn {
+		return t.Default
+	}
+	return NoTip
+}
+
+func (t TipSpecRx) defaultTip() Tip {
+	if t.Default.Kind() == TipKindOwn {
+		return NoTip
+	}
 	return t.Default
 }
 
@@ -82,6 +129,7 @@ type Config struct {
 	PtrTips    PtrTips    `yaml:"PtrTips"`
 	TypeTips   TypeTips   `yaml:"TypeTips"`
 	MemTips    MemTips    `yaml:"MemTips"`
+	ConstTypes ConstTypes `yaml:"ConstTypes"`
 	Typemap    CTypeMap   `yaml:"Typemap"`
 
 	IgnoredFiles []string `yaml:"-"`
@@ -95,12 +143,15 @@ func New(cfg *Config) (*Translator, error) {
 		rules:              cfg.Rules,
 		constRules:         cfg.ConstRules,
 		typemap:            cfg.Typemap,
+		builtinTypemap:     builtinCTypeMap,
+		pointerSize:        8,
 		compiledRxs:        make(map[RuleAction]RxMap),
 		compiledPtrTipRxs:  make(PtrTipRxMap),
 		compiledTypeTipRxs: make(TypeTipRxMap),
 		valueMap:           make(map[string]Value),
 		exprMap:            make(map[string]string),
 		tagMap:             make(map[string]*CDecl),
+		sources:            make(map[string][]string),
 		typedefsSet:        make(map[string]struct{}),
 		typedefKinds:       make(map[string]CTypeKind),
 		ignoredFiles:       make(map[string]struct{}),
@@ -108,6 +159,7 @@ func New(cfg *Config) (*Translator, error) {
 		ptrTipCache:        &TipCache{},
 		typeTipCache:       &TipCache{},
 		memTipCache:        &TipCache{},
+		diagnostics:        NewDiagnostics(),
 	}
 	for _, p := range cfg.IgnoredFiles {
 		t.ignoredFiles[p] = struct{}{}
@@ -134,6 +186,11 @@ func New(cfg *Config) (*Translator, error) {
 	} else {
 		t.compiledMemTipRxs = rxList
 	}
+	if rxList, err := getConstTypeRxs(cfg.ConstTypes); err != nil {
+		return nil, err
+	} else {
+		t.compiledConstTypes = rxList
+	}
 	return t, nil
 }
 
@@ -251,6 +308,26 @@ func getMemTipRxs(tips MemTips) (MemTipRxList, error) {
 	return list, nil
 }
 
+func getConstTypeRxs(types ConstTypes) ([]ConstTypeRx, error) {
+	var list []ConstTypeRx
+	for _, spec := range types {
+		if len(spec.Target) == 0 {
+			continue
+		}
+		rx, err := regexp.Compile(spec.Target)
+		if err != nil {
+			return nil, fmt.Errorf("translator: const type: invalid regexp %s", spec.Target)
+		} else if len(spec.Type) == 0 {
+			return nil, fmt.Errorf("translator: const type: no type provided for %s", spec.Target)
+		}
+		list = append(list, ConstTypeRx{
+			Target: rx,
+			Type:   spec.Type,
+		})
+	}
+	return list, nil
+}
+
 type declList []*CDecl
 
 func (s declList) Len() int      { return len(s) }
@@ -264,12 +341,43 @@ func (s declList) Less(i, j int) bool {
 }
 
 func (t *Translator) Learn(unit *cc.TranslationUnit) {
+	if unit.Model != nil {
+		t.builtinTypemap = builtinCTypeMapFor(unit.Model)
+		if ptr, ok := unit.Model.Items[cc.Ptr]; ok {
+			t.pointerSize = ptr.Size
+		}
+		for kind, item := range unit.Model.Items {
+			if abi, ok := item.More.(cc.ModelItem); ok {
+				if t.abiItems == nil {
+					t.abiItems = make(map[cc.Kind]cc.ModelItem)
+				}
+				t.abiItems[kind] = abi
+			}
+		}
+	}
+	t.bigEndian = isBigEndian(unit.Macros)
 	t.walkTranslationUnit(unit)
 	t.resolveTypedefs(t.typedefs)
 	sort.Sort(declList(t.declares))
 	sort.Sort(declList(t.typedefs))
 	t.collectDefines(t.declares, unit.Macros)
 	sort.Sort(declList(t.defines))
+	sort.Sort(declList(t.macros))
+}
+
+// isBigEndian reports whether the byte order predefined for the target is big-endian.
+func isBigEndian(macros map[int]*cc.Macro) bool {
+	for _, macro := range macros {
+		if string(macro.DefTok.S()) != "__BYTE_ORDER__" {
+			continue
+		}
+		for _, token := range macro.ReplacementToks() {
+			if src := cc.TokSrc(token); src == "__ORDER_BIG_ENDIAN__" || src == "4321" {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // This has been left intentionally.
@@ -318,14 +426,22 @@ func (t *Translator) collectDefines(declares []*CDecl, defines map[int]*cc.Macro
 
 	// double traverse because macros can depend on each other and the map
 	// brings a randomized order of them.
+	var fnLike []*cc.Macro
 	for _, macro := range defines {
 		if t.IsTokenIgnored(macro.DefTok.Pos()) {
 			continue
-		} else if macro.IsFnLike {
-			continue
 		}
 		name := string(macro.DefTok.S())
+		if macro.IsFnLike {
+			if !t.IsAcceptableName(TargetFunction, name) {
+				t.diagnostics.Add(DiagFiltered, macro.DefTok.Pos(), name, "rejected by the translator rules")
+				continue
+			}
+			fnLike = append(fnLike, macro)
+			continue
+		}
 		if !t.IsAcceptableName(TargetConst, name) {
+			t.diagnostics.Add(DiagFiltered, macro.DefTok.Pos(), name, "rejected by the translator rules")
 			continue
 		}
 		seen[name] = struct{}{}
@@ -340,7 +456,8 @@ func (t *Translator) collectDefines(declares []*CDecl, defines map[int]*cc.Macro
 			continue
 		}
 		expand := false
-		if t.constRules[ConstDefines] == ConstExpand {
+		switch t.constRules[ConstDefines] {
+		case ConstExpand, ConstTyped:
 			expand = true
 		}
 
@@ -349,6 +466,9 @@ func (t *Translator) collectDefines(declares []*CDecl, defines map[int]*cc.Macro
 			case nil: // unresolved value -> try to expand
 				expand = true
 			case bool: // ban bools
+				if len(macro.ReplacementToks()) > 0 {
+					t.diagnostics.Add(DiagSkipped, macro.DefTok.Pos(), name, "boolean macros are not supported")
+				}
 				continue
 			case cc.StringLitID:
 				macro.Value = fmt.Sprintf(`"%s"`, xc.Dict.S(int(val)))
@@ -359,101 +479,563 @@ func (t *Translator) collectDefines(declares []*CDecl, defines map[int]*cc.Macro
 					Name:     name,
 					Value:    Value(macro.Value),
 					Pos:      macro.DefTok.Pos(),
+					Doc:      t.docAt(macro.DefTok.Pos()),
 				})
 				continue
 			}
 		} else if _, ok := macro.Value.(bool); ok {
 			// ban bools
+			if len(macro.ReplacementToks()) > 0 {
+				t.diagnostics.Add(DiagSkipped, macro.DefTok.Pos(), name, "boolean macros are not supported")
+			}
 			continue
 		}
-		tokens := macro.ReplacementToks()
-		srcParts := make([]string, 0, len(tokens))
-		exprParts := make([]string, 0, len(tokens))
-		valid := true
-
-		// TODO: some state machine
-		needsTypecast := false
-		typecastValue := false
-		typecastValueParens := 0
+		expr, src, err := t.expandTokens(macro.ReplacementToks(), seen, nil)
+		if err != nil {
+			if macro.Value == nil {
+				t.diagnostics.Add(DiagSkipped, macro.DefTok.Pos(), name, err.Error())
+			} else {
+				// fallback to the evaluated value
+				t.diagnostics.Add(DiagDegraded, macro.DefTok.Pos(), name, err.Error()+", using the evaluated value")
+				t.defines = append(t.defines, &CDecl{
+					IsDefine: true,
+					Name:     name,
+					Value:    Value(macro.Value),
+					Pos:      macro.DefTok.Pos(),
+					Doc:      t.docAt(macro.DefTok.Pos()),
+				})
+			}
+			continue
+		}
+		t.defines = append(t.defines, &CDecl{
+			IsDefine:   true,
+			Name:       name,
+			Expression: expr,
+			Src:        src,
+			Pos:        macro.DefTok.Pos(),
+			Doc:        t.docAt(macro.DefTok.Pos()),
+		})
+	}
+	if t.constRules[ConstDefines] == ConstTyped {
+		t.typeDefines(defines)
+	}
+	t.collectMacros(fnLike, seen)
+}
 
-		for _, token := range tokens {
+// typeDefines assigns types to the defines collected. A type is either set in ConstTypes, or
+// inferred from a typecast to a typedef in the define, or from the usage of the define as a call
+// argument (see usedAsArgs), or shared by all the defines it references, e.g. FLAG_AB (FLAG_A | FLAG_B)
+// gets the type of FLAG_A and FLAG_B, while (FLAG_A * SCALE) stays untyped unless SCALE has the same type.
+func (t *Translator) typeDefines(defines map[int]*cc.Macro) {
+	macros := make(map[string]*cc.Macro, len(defines))
+	for _, macro := range defines {
+		macros[string(macro.DefTok.S())] = macro
+	}
+	declared := make(map[string]*CDecl, len(t.defines))
+	for _, decl := range t.defines {
+		declared[decl.Name] = decl
+	}
+	usage := t.usedAsArgs(defines, declared)
+	typeName := func(decl *CDecl) (string, bool) {
+		if name, ok := t.constTypeOf(decl.Name); ok {
+			return name, true
+		}
+		var cast string
+		for _, token := range macros[decl.Name].ReplacementToks() {
 			src := cc.TokSrc(token)
-			srcParts = append(srcParts, src)
-			switch token.Rune {
-			case cc.IDENTIFIER:
-				if _, ok := seen[src]; ok {
-					// const reference
-					exprParts = append(exprParts, string(t.TransformName(TargetConst, src, true)))
-				} else if _, ok := t.typedefsSet[src]; ok {
-					// type reference
-					needsTypecast = true
-					exprParts = append(exprParts, string(t.TransformName(TargetType, src, true)))
-				} else {
-					// an unresolved reference
-					valid = false
+			if _, ok := t.typedefsSet[src]; !ok {
+				continue
+			} else if len(cast) > 0 && cast != src {
+				return "", false
+			}
+			cast = src
+		}
+		if len(cast) > 0 {
+			return cast, true
+		}
+		name := usage[decl.Name]
+		return name, len(name) > 0
+	}
+	for _, decl := range t.defines {
+		name, ok := typeName(decl)
+		if !ok {
+			continue
+		}
+		typ, err := t.ParseTypeName(name)
+		if err != nil {
+			t.diagnostics.Add(DiagDegraded, decl.Pos, decl.Name, err.Error()+", left untyped")
+			continue
+		}
+		decl.Spec = typ
+	}
+	// typed defines can be referenced by others that are declared in any order
+	for changed := true; changed; {
+		changed = false
+		for _, decl := range t.defines {
+			if decl.Spec != nil || len(decl.Expression) == 0 {
+				continue
+			}
+			var typ CType
+			for _, token := range macros[decl.Name].ReplacementToks() {
+				ref, ok := declared[cc.TokSrc(token)]
+				if !ok {
+					continue
+				} else if ref.Spec == nil || typ != nil && typ.String() != ref.Spec.String() {
+					// an untyped reference is typed on the next pass, if ever
+					typ = nil
 					break
 				}
+				typ = ref.Spec
+			}
+			if typ != nil {
+				decl.Spec = typ
+				changed = true
+			}
+		}
+	}
+	// an untyped define that references typed ones would get their type in Go,
+	// so it takes the evaluated value instead
+	for _, decl := range t.defines {
+		if decl.Spec != nil || len(decl.Expression) == 0 {
+			continue
+		}
+		macro := macros[decl.Name]
+		switch macro.Value.(type) {
+		case int32, int64, uint32, uint64, float32, float64:
+		default:
+			continue
+		}
+		for _, token := range macro.ReplacementToks() {
+			if ref, ok := declared[cc.TokSrc(token)]; ok && ref.Spec != nil {
+				decl.Value = Value(macro.Value)
+				break
+			}
+		}
+	}
+}
+
+// collectMacros translates function-like macros into function declarations, the params
+// and the result share the same type. With the expand rule the body is translated into
+// a Go expression, with the cgo rule the macro is left for a C wrapper to call.
+func (t *Translator) collectMacros(macros []*cc.Macro, seen map[string]struct{}) {
+	rule := t.constRules[ConstMacros]
+	for _, macro := range macros {
+		pos := macro.DefTok.Pos()
+		name := string(macro.DefTok.S())
+		if rule != ConstExpand && rule != ConstCGOAlias {
+			t.diagnostics.Add(DiagSkipped, pos, name, "function-like macros are not translated unless the macros const rule is set")
+			continue
+		}
+		tokens := macro.ReplacementToks()
+		if len(tokens) == 0 {
+			t.diagnostics.Add(DiagSkipped, pos, name, "function-like macro has an empty body")
+			continue
+		}
+		typ, err := t.macroType(name, tokens)
+		if err != nil {
+			t.diagnostics.Add(DiagSkipped, pos, name, err.Error())
+			continue
+		}
+		spec := &CFunctionSpec{
+			Raw:    name,
+			Return: typ,
+		}
+		params := make(map[string]string, len(macro.Args))
+		for _, id := range macro.Args {
+			argName := xc.Dict.S(id)
+			params[string(argName)] = blessName(argName)
+			spec.Params = append(spec.Params, &CDecl{
+				Name: blessName(argName),
+				Spec: typ,
+				Pos:  pos,
+			})
+		}
+		decl := &CDecl{
+			IsDefine: true,
+			Name:     name,
+			Spec:     spec,
+			Pos:      pos,
+			Doc:      t.docAt(pos),
+		}
+		if rule == ConstExpand {
+			expr, src, err := t.expandTokens(tokens, seen, params)
+			if err != nil {
+				t.diagnostics.Add(DiagSkipped, pos, name, err.Error())
+				continue
+			}
+			decl.Expression = expr
+			decl.Src = src
+		} else {
+			srcParts := make([]string, 0, len(tokens))
+			for _, token := range tokens {
+				if src := cc.TokSrc(token); src == "__VA_ARGS__" {
+					err = errors.New("variadic macros are not supported")
+					break
+				} else {
+					srcParts = append(srcParts, src)
+				}
+			}
+			if err != nil {
+				t.diagnostics.Add(DiagSkipped, pos, name, err.Error())
+				continue
+			}
+			decl.Src = strings.Join(srcParts, " ")
+		}
+		t.macros = append(t.macros, decl)
+	}
+}
+
+// usedAsArgs finds the defines passed as arguments of the declared functions within the macro bodies,
+// e.g. foo_set_flags(FLAG_A | FLAG_B), and maps them to the typedef names of the params they are passed to.
+// Only the arguments that combine defines with bitwise operators are considered, and a define used
+// for params of different types is left out. The function bodies are not looked into.
+func (t *Translator) usedAsArgs(defines map[int]*cc.Macro, declared map[string]*CDecl) map[string]string {
+	funcs := make(map[string]*CFunctionSpec)
+	for _, decl := range t.declares {
+		if spec, ok := decl.Spec.(*CFunctionSpec); ok {
+			funcs[decl.Name] = spec
+		}
+	}
+	usage := make(map[string]string)
+	conflicts := make(map[string]struct{})
+	use := func(arg []xc.Token, typeName string) {
+		var names []string
+		for _, token := range arg {
+			switch src := cc.TokSrc(token); {
+			case token.Rune == cc.IDENTIFIER:
+				if _, ok := declared[src]; !ok {
+					return
+				}
+				names = append(names, src)
+			case src == "|" || src == "&" || src == "~" || src == "(" || src == ")":
 			default:
-				// TODO: state machine
-				const (
-					lparen = rune(40)
-					rparen = rune(41)
-				)
-				switch {
-				case needsTypecast && token.Rune == rparen:
-					typecastValue = true
-					needsTypecast = false
-					exprParts = append(exprParts, src+"(")
-				case typecastValue && token.Rune == lparen:
-					typecastValueParens++
-				case typecastValue && token.Rune == rparen:
-					if typecastValueParens == 0 {
-						typecastValue = false
-						exprParts = append(exprParts, ")"+src)
-					} else {
-						typecastValueParens--
+				return
+			}
+		}
+		for _, name := range names {
+			if used, ok := usage[name]; ok && used != typeName {
+				conflicts[name] = struct{}{}
+			}
+			usage[name] = typeName
+		}
+	}
+	for _, macro := range defines {
+		tokens := macro.ReplacementToks()
+		for i := 0; i+1 < len(tokens); i++ {
+			spec, ok := funcs[cc.TokSrc(tokens[i])]
+			if !ok || tokens[i].Rune != cc.IDENTIFIER || tokens[i+1].Rune != '(' {
+				continue
+			}
+			end := matchingParen(tokens, i+1)
+			if end < 0 {
+				break
+			}
+			var depth, param int
+			start := i + 2
+			for j := start; j <= end; j++ {
+				switch tokens[j].Rune {
+				case '(':
+					depth++
+					continue
+				case ')':
+					if j < end {
+						depth--
+						continue
 					}
-				default:
-					// somewhere in the world a kitten died because of this
-					if token.Rune == '~' {
-						src = "^"
+				case ',':
+					if depth > 0 {
+						continue
 					}
-					if runes := []rune(src); len(runes) > 0 && isNumeric(runes) {
-						// TODO(xlab): better const handling
-						src = readNumeric(runes)
+				default:
+					continue
+				}
+				if param < len(spec.Params) {
+					if typeName, ok := t.typedefParam(spec.Params[param]); ok {
+						use(tokens[start:j], typeName)
 					}
-					exprParts = append(exprParts, src)
 				}
+				param++
+				start = j + 1
 			}
-			if !valid {
-				break
+		}
+	}
+	for name := range conflicts {
+		delete(usage, name)
+	}
+	return usage
+}
+
+// typedefParam returns the name of the typedef or the enum typedef a param is declared with, if it's a value one.
+func (t *Translator) typedefParam(param *CDecl) (string, bool) {
+	var name string
+	switch spec := param.Spec.(type) {
+	case *CTypeSpec:
+		if spec.Pointers > 0 {
+			return "", false
+		}
+		name = spec.Raw
+	case *CEnumSpec:
+		if spec.Pointers > 0 {
+			return "", false
+		}
+		name = spec.Typedef
+	}
+	if _, ok := t.typedefsSet[name]; !ok || len(name) == 0 {
+		return "", false
+	}
+	return name, true
+}
+
+// constTypeOf returns the C type name set in ConstTypes for the define or macro.
+func (t *Translator) constTypeOf(name string) (string, bool) {
+	for _, rx := range t.compiledConstTypes {
+		if rx.Target.MatchString(name) {
+			return rx.Type, true
+		}
+	}
+	return "", false
+}
+
+// macroType returns the type of a function-like macro, it's either set in ConstTypes
+// or inferred from the literals: double if any of them is a floating point one, int64 otherwise.
+func (t *Translator) macroType(name string, tokens []xc.Token) (CType, error) {
+	typeName := "long long"
+	for _, token := range tokens {
+		if runes := []rune(cc.TokSrc(token)); isNumeric(runes) && isFloating(runes) {
+			typeName = "double"
+			break
+		}
+	}
+	if name, ok := t.constTypeOf(name); ok {
+		typeName = name
+	}
+	typ, err := t.ParseTypeName(typeName)
+	if err != nil {
+		return nil, err
+	}
+	switch typ.Kind() {
+	case TypeKind, EnumKind:
+		if typ.GetPointers() == 0 {
+			if spec, ok := typ.(*CTypeSpec); !ok || spec.Base != "void*" {
+				return typ, nil
 			}
 		}
-		if typecastValue {
-			// still in typecast value, need to close paren
-			exprParts = append(exprParts, ")")
-			typecastValue = false
+	}
+	return nil, fmt.Errorf("type %s is not a numeric one", typeName)
+}
+
+// macroFuncForbidden lists the tokens that are not allowed in the body of a function-like
+// macro translated into Go: the ones with side effects, the ones that yield a C boolean
+// and the statements or constructs that have no counterpart in a Go expression.
+var macroFuncForbidden = map[string]struct{}{
+	"=": {}, "+=": {}, "-=": {}, "*=": {}, "/=": {}, "%=": {}, "&=": {}, "|=": {}, "^=": {},
+	"<<=": {}, ">>=": {}, "++": {}, "--": {}, ";": {}, "{": {}, "}": {}, "?": {}, ":": {},
+	",": {}, "#": {}, "##": {}, "!": {}, "&&": {}, "||": {}, "==": {}, "!=": {},
+	"<": {}, ">": {}, "<=": {}, ">=": {}, "[": {}, "]": {}, ".": {}, "->": {},
+	"sizeof": {}, "__VA_ARGS__": {},
+}
+
+// expandTokens translates the replacement tokens of a macro into a Go expression, the identifiers
+// must reference the constants seen or, within casts, the known types. For a function-like macro,
+// params map its args to the Go param names, the tokens are checked against macroFuncForbidden
+// and the numeric literals are left untyped.
+func (t *Translator) expandTokens(tokens []xc.Token, seen map[string]struct{}, params map[string]string) (expr, src string, err error) {
+	srcParts := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		src := cc.TokSrc(token)
+		srcParts = append(srcParts, src)
+		if params != nil {
+			if _, ok := macroFuncForbidden[src]; ok {
+				return "", "", fmt.Errorf("unsupported token %s in function-like macro", src)
+			}
+			switch token.Rune {
+			case cc.STRINGLITERAL, cc.LONGSTRINGLITERAL:
+				return "", "", errors.New("string literals are not supported in function-like macro")
+			}
 		}
-		if !valid {
-			if macro.Value != nil {
-				// fallback to the evaluated value
-				t.defines = append(t.defines, &CDecl{
-					IsDefine: true,
-					Name:     name,
-					Value:    Value(macro.Value),
-					Pos:      macro.DefTok.Pos(),
-				})
+	}
+	exprParts, err := t.expandExpr(tokens, seen, params)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.Join(exprParts, " "), strings.Join(srcParts, " "), nil
+}
+
+// expandExpr translates the tokens one by one, except for the C casts that become Go conversions
+// of their operand: (uint32_t)(a) << 1 turns into uint32(a) << 1.
+func (t *Translator) expandExpr(tokens []xc.Token, seen map[string]struct{}, params map[string]string) ([]string, error) {
+	exprParts := make([]string, 0, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		src := cc.TokSrc(token)
+		if typeEnd := t.castTypeEnd(tokens, i, params); typeEnd > 0 {
+			goType, err := t.castType(tokens[i+1 : typeEnd])
+			if err != nil {
+				return nil, err
+			}
+			operandEnd := t.operandEnd(tokens, typeEnd+1, params)
+			if operandEnd < 0 {
+				return nil, fmt.Errorf("cast to %s has no operand", goType)
+			}
+			operand := tokens[typeEnd+1 : operandEnd]
+			if len(operand) > 1 && operand[0].Rune == '(' && matchingParen(operand, 0) == len(operand)-1 &&
+				t.castTypeEnd(operand, 0, params) < 0 {
+				// the parens of the operand become the ones of the conversion
+				operand = operand[1 : len(operand)-1]
 			}
+			inner, err := t.expandExpr(operand, seen, params)
+			if err != nil {
+				return nil, err
+			}
+			exprParts = append(exprParts, goType+"("+strings.Join(inner, " ")+")")
+			i = operandEnd - 1
 			continue
 		}
-		t.defines = append(t.defines, &CDecl{
-			IsDefine:   true,
-			Name:       name,
-			Expression: strings.Join(exprParts, " "),
-			Src:        strings.Join(srcParts, " "),
-			Pos:        macro.DefTok.Pos(),
-		})
+		switch token.Rune {
+		case cc.IDENTIFIER:
+			if name, ok := params[src]; ok {
+				// param reference
+				exprParts = append(exprParts, name)
+			} else if _, ok := seen[src]; ok {
+				// const reference
+				exprParts = append(exprParts, string(t.TransformName(TargetConst, src, true)))
+			} else if _, ok := t.typedefsSet[src]; ok {
+				return nil, fmt.Errorf("type %s is referenced outside of a cast", src)
+			} else {
+				// an unresolved reference
+				return nil, fmt.Errorf("unresolved reference to %s", src)
+			}
+		default:
+			if _, ok := cTypeWords[src]; ok {
+				return nil, fmt.Errorf("type %s is referenced outside of a cast", src)
+			}
+			// somewhere in the world a kitten died because of this
+			if token.Rune == '~' {
+				src = "^"
+			}
+			if runes := []rune(src); len(runes) > 0 && isNumeric(runes) {
+				// TODO(xlab): better const handling
+				if params != nil {
+					src = readUntypedNumeric(runes)
+				} else {
+					src = readNumeric(runes)
+				}
+			}
+			exprParts = append(exprParts, src)
+		}
+	}
+	return exprParts, nil
+}
+
+// cTypeWords lists the keywords that make up the C type names in casts.
+var cTypeWords = map[string]struct{}{
+	"void": {}, "char": {}, "short": {}, "int": {}, "long": {}, "float": {}, "double": {},
+	"signed": {}, "unsigned": {}, "const": {}, "_Bool": {},
+}
+
+// castTypeEnd returns the index of the closing paren if the tokens starting at i
+// form a cast like (unsigned int) or (uint32_t *), or -1 otherwise.
+func (t *Translator) castTypeEnd(tokens []xc.Token, i int, params map[string]string) int {
+	if i >= len(tokens) || tokens[i].Rune != '(' {
+		return -1
+	}
+	j := i + 1
+	for ; j < len(tokens); j++ {
+		src := cc.TokSrc(tokens[j])
+		if _, ok := cTypeWords[src]; ok {
+			continue
+		} else if tokens[j].Rune == cc.IDENTIFIER {
+			if _, ok := params[src]; ok {
+				return -1
+			} else if _, ok := t.typedefsSet[src]; ok {
+				continue
+			}
+			return -1
+		} else if src == "*" && j > i+1 {
+			continue
+		}
+		break
+	}
+	if j == i+1 || j == len(tokens) || tokens[j].Rune != ')' {
+		return -1
+	}
+	return j
+}
+
+// castType returns the Go type a cast converts to, only the numeric types
+// have a conversion that keeps the meaning of the C cast.
+func (t *Translator) castType(tokens []xc.Token) (string, error) {
+	words := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		words = append(words, cc.TokSrc(token))
+	}
+	typeName := strings.Join(words, " ")
+	typ, err := t.ParseTypeName(typeName)
+	if err != nil {
+		return "", err
+	}
+	goSpec := t.TranslateSpec(typ)
+	if goSpec.Pointers > 0 || goSpec.Slices > 0 || len(goSpec.InnerArr) > 0 || len(goSpec.OuterArr) > 0 {
+		return "", fmt.Errorf("cast to %s is not supported", typeName)
+	}
+	switch goSpec.Kind {
+	case StructKind, OpaqueStructKind, UnionKind, FunctionKind:
+		return "", fmt.Errorf("cast to %s is not supported", typeName)
+	}
+	switch goSpec.Base {
+	case "unsafe.Pointer", "string", "bool", "void":
+		return "", fmt.Errorf("cast to %s is not supported", typeName)
+	}
+	if len(goSpec.Base) == 0 && len(goSpec.Raw) == 0 {
+		return "", fmt.Errorf("cast to %s is not supported", typeName)
+	}
+	return goSpec.String(), nil
+}
+
+// operandEnd returns the end of the unary expression starting at i: the literal, the name,
+// the parenthesized group or another cast, with the unary operators that precede it.
+func (t *Translator) operandEnd(tokens []xc.Token, i int, params map[string]string) int {
+	for i < len(tokens) {
+		switch tokens[i].Rune {
+		case '-', '+', '~':
+			i++
+			continue
+		}
+		break
+	}
+	if i >= len(tokens) {
+		return -1
 	}
+	if typeEnd := t.castTypeEnd(tokens, i, params); typeEnd > 0 {
+		return t.operandEnd(tokens, typeEnd+1, params)
+	}
+	if tokens[i].Rune == '(' {
+		if end := matchingParen(tokens, i); end > 0 {
+			return end + 1
+		}
+		return -1
+	}
+	if tokens[i].Rune == ')' {
+		return -1
+	}
+	return i + 1
+}
+
+// matchingParen returns the index of the paren closing the one at i, or -1.
+func matchingParen(tokens []xc.Token, i int) int {
+	var depth int
+	for j := i; j < len(tokens); j++ {
+		switch tokens[j].Rune {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return -1
 }
 
 func (t *Translator) resolveTypedefs(typedefs []*CDecl) {
@@ -554,7 +1136,7 @@ func (t *Translator) lookupSpec(spec CTypeSpec) (GoTypeSpec, bool) {
 	if gospec, ok := t.typemap[spec]; ok {
 		return gospec, true
 	}
-	if gospec, ok := builtinCTypeMap[spec]; ok {
+	if gospec, ok := t.builtinTypemap[spec]; ok {
 		return gospec, true
 	}
 	if spec.Const {
@@ -562,7 +1144,7 @@ func (t *Translator) lookupSpec(spec CTypeSpec) (GoTypeSpec, bool) {
 		if gospec, ok := t.typemap[spec]; ok {
 			return gospec, true
 		}
-		if gospec, ok := builtinCTypeMap[spec]; ok {
+		if gospec, ok := t.builtinTypemap[spec]; ok {
 			return gospec, true
 		}
 	}
@@ -782,6 +1364,12 @@ func (t *Translator) TranslateSpec(spec CType, tips ...Tip) GoTypeSpec {
 		if t.IsAcceptableName(TargetType, typeSpec.Raw) {
 			wrapper.Raw = string(t.TransformName(TargetType, typeSpec.Raw))
 		}
+		if _, ok := t.typedefsSet[lookupSpec.Base]; !ok {
+			if _, ok := t.tagMap[lookupSpec.Base]; !ok {
+				t.diagnostics.Add(DiagDegraded, token.NoPos, lookupSpec.Base,
+					"unknown type, translated by its name")
+			}
+		}
 		wrapper.Base = string(t.TransformName(TargetType, lookupSpec.Base))
 		switch wrapper.Kind {
 		case TypeKind:
@@ -831,7 +1419,8 @@ func (t *Translator) TranslateSpec(spec CType, tips ...Tip) GoTypeSpec {
 				decl, tagKnown = t.tagMap[fspec.Typedef]
 			}
 			if tagKnown {
-				if !decl.Spec.IsOpaque() {
+				if !decl.Spec.IsOpaque() && wrapper.Kind != UnionKind {
+					// unions stay plain byte arrays even when members are known
 					wrapper.Kind = StructKind
 				}
 				// count in the pointers of the base type under typedef
@@ -866,6 +1455,79 @@ func (t *Translator) NormalizeSpecPointers(spec CType) CType {
 	return spec
 }
 
+// ParseTypeName builds a type spec out of a C type name like "unsigned long" or "const char *",
+// typedef names are resolved against the declarations learned so far.
+func (t *Translator) ParseTypeName(name string) (CType, error) {
+	str := strings.TrimSpace(name)
+	var pointers uint8
+	for strings.HasSuffix(str, "*") {
+		str = strings.TrimSpace(strings.TrimSuffix(str, "*"))
+		pointers++
+	}
+	spec := &CTypeSpec{}
+	var base string
+	var longs int
+	for _, word := range strings.Fields(str) {
+		switch word {
+		case "const":
+			spec.Const = true
+		case "unsigned":
+			spec.Unsigned = true
+		case "signed":
+			spec.Signed = true
+		case "short":
+			spec.Short = true
+		case "long":
+			longs++
+		default:
+			if len(base) > 0 {
+				return nil, fmt.Errorf("translator: unsupported type name: %s", name)
+			}
+			base = word
+		}
+	}
+	switch base {
+	case "", "int":
+		spec.Base = "int"
+		switch longs {
+		case 0:
+		case 1:
+			spec.Long = true
+		default:
+			spec.Base = "long"
+			spec.Long = true
+		}
+	case "char":
+		spec.Base = "char"
+	case "float":
+		spec.Base = "float"
+	case "double":
+		spec.Base = "double"
+		spec.Long = longs > 0
+	case "_Bool", "bool":
+		spec.Base = "_Bool"
+	case "void":
+		if pointers == 0 {
+			return nil, fmt.Errorf("translator: unsupported type name: %s", name)
+		}
+		spec.Base = "void*"
+		pointers--
+	default:
+		for _, decl := range t.typedefs {
+			if decl.Name != base {
+				continue
+			}
+			typ := decl.Spec.Copy()
+			typ.SetRaw(base)
+			typ.SetPointers(typ.GetPointers() + pointers)
+			return typ, nil
+		}
+		return nil, fmt.Errorf("translator: unknown type name: %s", name)
+	}
+	spec.Pointers = pointers
+	return spec, nil
+}
+
 func (t *Translator) CGoSpec(spec CType, asArg bool) CGoSpec {
 	cgo := CGoSpec{
 		Pointers: spec.GetPointers(),
@@ -982,6 +1644,12 @@ func (t *Translator) IsAcceptableName(target RuleTarget, name string) bool {
 	return false
 }
 
+// Diagnostics returns the collector of the skipped and degraded declarations,
+// it's shared with the generator.
+func (t *Translator) Diagnostics() *Diagnostics {
+	return t.diagnostics
+}
+
 func (t *Translator) TagMap() map[string]*CDecl {
 	return t.tagMap
 }
@@ -998,6 +1666,20 @@ func (t *Translator) Defines() []*CDecl {
 	return t.defines
 }
 
+// PointerSize returns the size of pointers in the data model of the learned unit.
+func (t *Translator) PointerSize() int {
+	return t.pointerSize
+}
+
+// Macros returns the function-like macros translated according to the macros const rule.
+func (t *Translator) Macros() []*CDecl {
+	return t.macros
+}
+
+func (t *Translator) ConstRules() ConstRules {
+	return t.constRules
+}
+
 func (t *Translator) Declares() []*CDecl {
 	return t.declares
 }
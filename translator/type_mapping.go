@@ -1,5 +1,7 @@
 package translator
 
+import "modernc.org/cc"
+
 type CTypeMap map[CTypeSpec]GoTypeSpec
 type GoTypeMap map[string]GoTypeSpec
 
@@ -99,3 +101,55 @@ var builtinCTypeMap = CTypeMap{
 	// _Bool -> bool
 	CTypeSpec{Base: "_Bool"}: BoolSpec,
 }
+
+// builtinCTypeMapFor returns the builtin map with the integer widths taken from
+// the model the sources have been parsed with, so long becomes int32 on ILP32
+// and LLP64 models and int64 on LP64 models.
+func builtinCTypeMapFor(model *cc.Model) CTypeMap {
+	typemap := make(CTypeMap, len(builtinCTypeMap))
+	for cspec, gospec := range builtinCTypeMap {
+		typemap[cspec] = gospec
+	}
+	if model == nil {
+		return typemap
+	}
+	sized := func(kind cc.Kind, cspecs ...CTypeSpec) {
+		item, ok := model.Items[kind]
+		if !ok || item.Size == 0 {
+			return
+		}
+		for _, cspec := range cspecs {
+			typemap[cspec] = GoTypeSpec{
+				Base:     "int",
+				Bits:     uint16(item.Size * 8),
+				Unsigned: cspec.Unsigned,
+			}
+		}
+	}
+	sized(cc.Short,
+		CTypeSpec{Base: "short"},
+		CTypeSpec{Base: "int", Short: true},
+		CTypeSpec{Base: "int", Short: true, Signed: true})
+	sized(cc.UShort,
+		CTypeSpec{Base: "short", Unsigned: true},
+		CTypeSpec{Base: "int", Short: true, Unsigned: true})
+	sized(cc.Int,
+		CTypeSpec{Base: "int"},
+		CTypeSpec{Base: "int", Signed: true})
+	sized(cc.UInt,
+		CTypeSpec{Base: "int", Unsigned: true})
+	sized(cc.Long,
+		CTypeSpec{Base: "long"},
+		CTypeSpec{Base: "long", Signed: true},
+		CTypeSpec{Base: "int", Long: true},
+		CTypeSpec{Base: "int", Long: true, Signed: true})
+	sized(cc.ULong,
+		CTypeSpec{Base: "long", Unsigned: true},
+		CTypeSpec{Base: "int", Long: true, Unsigned: true})
+	sized(cc.LongLong,
+		CTypeSpec{Base: "long", Long: true},
+		CTypeSpec{Base: "long", Long: true, Signed: true})
+	sized(cc.ULongLong,
+		CTypeSpec{Base: "long", Long: true, Unsigned: true})
+	return typemap
+}
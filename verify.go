@@ -0,0 +1,398 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	tl "github.com/xlab/c-for-go/translator"
+)
+
+// Verify type-checks the Go files written by Flush against the C headers they
+// reference, so broken bindings are spotted before a downstream user runs go build.
+// When stub is set, the package is also built and linked against a stub library
+// that defines every declared C function. Each failure is linked back to the
+// C declaration the failing code has been generated from.
+func (c *Process) Verify(stub bool) error {
+	pkgDir, err := filepath.Abs(filepath.Join(c.outputPath, c.cfg.Generator.PackageName))
+	if err != nil {
+		return err
+	}
+	bp, err := build.ImportDir(pkgDir, 0)
+	if err != nil {
+		return err
+	}
+	tmpDir, err := ioutil.TempDir("", "c-for-go-verify")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	v := &verifier{
+		proc:    c,
+		pkgDir:  pkgDir,
+		fset:    token.NewFileSet(),
+		files:   make(map[string]*ast.File),
+		origins: c.declOrigins(),
+	}
+	if err := v.parseSources(bp); err != nil {
+		return err
+	}
+	if err := v.checkTypes(bp, filepath.Join(tmpDir, "obj")); err != nil {
+		return err
+	}
+	if stub && len(v.failures) == 0 {
+		if err := v.checkLink(filepath.Join(tmpDir, "stub")); err != nil {
+			return err
+		}
+	}
+	if len(v.failures) > 0 {
+		return fmt.Errorf("verify: %d problem(s) found in %s:\n%s",
+			len(v.failures), pkgDir, strings.Join(v.failures, "\n"))
+	}
+	return nil
+}
+
+// declOrigin refers to the C declaration a Go name has been generated from.
+type declOrigin struct {
+	cName    string
+	location string
+}
+
+func (o declOrigin) String() string {
+	return fmt.Sprintf("%s as declared in %s", o.cName, o.location)
+}
+
+// declOrigins maps the Go names of the translated declarations to their origins.
+func (c *Process) declOrigins() map[string]declOrigin {
+	origins := make(map[string]declOrigin)
+	add := func(target tl.RuleTarget, decl *tl.CDecl, name string) {
+		if len(name) == 0 {
+			return
+		}
+		goName := string(c.tr.TransformName(target, name))
+		if _, ok := origins[goName]; ok {
+			return
+		}
+		origins[goName] = declOrigin{
+			cName:    name,
+			location: filepath.ToSlash(c.tr.Location(decl.Pos)),
+		}
+	}
+	for _, decl := range c.tr.Declares() {
+		if decl.Spec.Kind() == tl.FunctionKind {
+			add(tl.TargetFunction, decl, decl.Name)
+			continue
+		}
+		add(tl.TargetPublic, decl, decl.Name)
+	}
+	for _, decl := range c.tr.Typedefs() {
+		add(tl.TargetType, decl, decl.Name)
+	}
+	for tag, decl := range c.tr.TagMap() {
+		add(tl.TargetType, decl, tag)
+	}
+	for _, decl := range c.tr.Defines() {
+		add(tl.TargetConst, decl, decl.Name)
+	}
+	for _, decl := range c.tr.Macros() {
+		add(tl.TargetFunction, decl, decl.Name)
+	}
+	return origins
+}
+
+type verifier struct {
+	proc     *Process
+	pkgDir   string
+	fset     *token.FileSet
+	files    map[string]*ast.File
+	origins  map[string]declOrigin
+	failures []string
+}
+
+func (v *verifier) parseSources(bp *build.Package) error {
+	var names []string
+	names = append(names, bp.GoFiles...)
+	names = append(names, bp.CgoFiles...)
+	for _, name := range names {
+		path := filepath.Join(v.pkgDir, name)
+		f, err := parser.ParseFile(v.fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		v.files[path] = f
+	}
+	return nil
+}
+
+// cflags returns the C compiler flags needed to locate the headers.
+func (v *verifier) cflags() []string {
+	var flags []string
+	for _, path := range v.proc.cfg.Parser.IncludePaths {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		flags = append(flags, "-I"+path)
+	}
+	return flags
+}
+
+// checkTypes runs cgo over the generated files and type-checks its output.
+func (v *verifier) checkTypes(bp *build.Package, objDir string) error {
+	if err := os.MkdirAll(objDir, 0755); err != nil {
+		return err
+	}
+	var goFiles []string
+	for _, name := range bp.GoFiles {
+		goFiles = append(goFiles, filepath.Join(v.pkgDir, name))
+	}
+	if len(bp.CgoFiles) > 0 {
+		args := []string{"tool", "cgo", "-objdir", objDir, "--"}
+		args = append(args, v.cflags()...)
+		args = append(args, bp.CgoCPPFLAGS...)
+		args = append(args, bp.CgoCFLAGS...)
+		args = append(args, bp.CgoFiles...)
+		cmd := exec.Command("go", args...)
+		cmd.Dir = v.pkgDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			v.reportOutput(out, "cgo: "+err.Error())
+			return nil
+		}
+		for _, name := range bp.CgoFiles {
+			goFiles = append(goFiles, filepath.Join(objDir,
+				strings.TrimSuffix(name, ".go")+".cgo1.go"))
+		}
+		goFiles = append(goFiles, filepath.Join(objDir, "_cgo_gotypes.go"))
+	}
+
+	fset := token.NewFileSet()
+	files := make([]*ast.File, 0, len(goFiles))
+	for _, path := range goFiles {
+		f, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+	}
+	conf := types.Config{
+		Importer: importer.Default(),
+		Error: func(err error) {
+			if terr, ok := err.(types.Error); ok {
+				pos := terr.Fset.Position(terr.Pos)
+				v.report(pos.Filename, pos.Line, terr.Msg)
+				return
+			}
+			v.failures = append(v.failures, err.Error())
+		},
+	}
+	// the errors have been collected by the handler above
+	conf.Check(bp.Name, fset, files, nil)
+	return nil
+}
+
+var ldflagsRx = regexp.MustCompile(`(?m)^#cgo\b[^:\n]*\bLDFLAGS:.*\n`)
+
+// checkLink copies the generated package into dir, replaces its LDFLAGS with
+// a stub definition of each declared C function and builds the package.
+func (v *verifier) checkLink(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	infos, err := ioutil.ReadDir(v.pkgDir)
+	if err != nil {
+		return err
+	}
+	for _, info := range infos {
+		if info.IsDir() {
+			continue
+		}
+		data, err := ioutil.ReadFile(filepath.Join(v.pkgDir, info.Name()))
+		if err != nil {
+			return err
+		}
+		if filepath.Ext(info.Name()) == ".go" {
+			data = ldflagsRx.ReplaceAll(data, nil)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, info.Name()), data, 0644); err != nil {
+			return err
+		}
+	}
+	stubSrc := new(bytes.Buffer)
+	fmt.Fprintln(stubSrc, "// Stub definitions of the declared C symbols, used for verification only.")
+	for _, decl := range v.proc.tr.Declares() {
+		if !v.proc.tr.IsAcceptableName(tl.TargetPublic, decl.Name) {
+			continue
+		}
+		if decl.Spec.Kind() == tl.FunctionKind {
+			fmt.Fprintf(stubSrc, "void %s(void) {}\n", decl.Name)
+			continue
+		}
+		fmt.Fprintf(stubSrc, "char %s[64];\n", decl.Name)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "c_for_go_stub.c"), stubSrc.Bytes(), 0644); err != nil {
+		return err
+	}
+	goMod := []byte("module c_for_go_verify\n\ngo 1.14\n")
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), goMod, 0644); err != nil {
+		return err
+	}
+	// undefined C symbols are reported by the linker only, so a binary is built
+	mainDir := filepath.Join(dir, "cmd")
+	if err := os.MkdirAll(mainDir, 0755); err != nil {
+		return err
+	}
+	mainSrc := []byte("package main\n\nimport _ \"c_for_go_verify\"\n\nfunc main() {}\n")
+	if err := ioutil.WriteFile(filepath.Join(mainDir, "main.go"), mainSrc, 0644); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", "build", "-o", os.DevNull, "./cmd")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "CGO_ENABLED=1",
+		"CGO_CFLAGS="+strings.Join(append(strings.Fields(os.Getenv("CGO_CFLAGS")), v.cflags()...), " "))
+	if out, err := cmd.CombinedOutput(); err != nil {
+		v.reportOutput(out, "link: "+err.Error())
+	}
+	return nil
+}
+
+var (
+	goPosRx     = regexp.MustCompile(`^(.+\.go):(\d+)(?::\d+)?: (.*)$`)
+	undefinedRx = regexp.MustCompile("undefined reference to [`'\"]?([A-Za-z_][A-Za-z0-9_]*)")
+)
+
+// reportOutput collects failures from the output of a go tool, the lines that
+// point at the generated code or at undefined C symbols get linked to the origins.
+func (v *verifier) reportOutput(out []byte, fallback string) {
+	var reported bool
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if m := goPosRx.FindStringSubmatch(line); m != nil {
+			path := m[1]
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(v.pkgDir, filepath.Base(path))
+			}
+			n, _ := strconv.Atoi(m[2])
+			v.report(path, n, m[3])
+			reported = true
+		} else if m := undefinedRx.FindStringSubmatch(line); m != nil {
+			reported = true
+			if seen[m[1]] {
+				continue
+			}
+			seen[m[1]] = true
+			msg := fmt.Sprintf("C symbol %s is undefined", m[1])
+			for _, origin := range v.origins {
+				if origin.cName == m[1] {
+					msg = fmt.Sprintf("%s [%s]", msg, origin)
+					break
+				}
+			}
+			v.failures = append(v.failures, msg)
+		}
+	}
+	if !reported {
+		v.failures = append(v.failures, fmt.Sprintf("%s\n%s", fallback, bytes.TrimSpace(out)))
+	}
+}
+
+// report records a failure at the given position in the generated code.
+func (v *verifier) report(path string, line int, msg string) {
+	location := fmt.Sprintf("%s:%d", filepath.Base(path), line)
+	subject := v.subjectAt(path, line)
+	if len(subject) == 0 {
+		v.failures = append(v.failures, fmt.Sprintf("%s: %s", location, msg))
+		return
+	}
+	if origin, ok := v.originOf(subject); ok {
+		v.failures = append(v.failures, fmt.Sprintf("%s: %s: %s [%s]", location, subject, msg, origin))
+		return
+	}
+	v.failures = append(v.failures, fmt.Sprintf("%s: %s: %s", location, subject, msg))
+}
+
+// subjectAt returns the name of the top-level declaration enclosing the line,
+// methods are named after their receiver's type.
+func (v *verifier) subjectAt(path string, line int) string {
+	f, ok := v.files[path]
+	if !ok {
+		return ""
+	}
+	for _, decl := range f.Decls {
+		if line < v.fset.Position(decl.Pos()).Line || line > v.fset.Position(decl.End()).Line {
+			continue
+		}
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Recv == nil || len(decl.Recv.List) == 0 {
+				return decl.Name.Name
+			}
+			recv := decl.Recv.List[0].Type
+			if star, ok := recv.(*ast.StarExpr); ok {
+				recv = star.X
+			}
+			if ident, ok := recv.(*ast.Ident); ok {
+				return ident.Name + "." + decl.Name.Name
+			}
+			return decl.Name.Name
+		case *ast.GenDecl:
+			for _, spec := range decl.Specs {
+				if line < v.fset.Position(spec.Pos()).Line || line > v.fset.Position(spec.End()).Line {
+					continue
+				}
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					return spec.Name.Name
+				case *ast.ValueSpec:
+					return spec.Names[0].Name
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// originOf finds the declaration origin of the subject. The helpers are named
+// after the types they serve, so the longest known name within the subject is
+// used when there is no exact match.
+func (v *verifier) originOf(subject string) (declOrigin, bool) {
+	name := subject
+	if idx := strings.Index(name, "."); idx > 0 {
+		name = name[:idx]
+	}
+	if origin, ok := v.origins[name]; ok {
+		return origin, true
+	} else if origin, ok := v.origins[strings.TrimPrefix(name, "g")]; ok {
+		return origin, true
+	}
+	var candidates []string
+	for goName := range v.origins {
+		if strings.Contains(subject, goName) {
+			candidates = append(candidates, goName)
+		}
+	}
+	if len(candidates) == 0 {
+		return declOrigin{}, false
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if len(candidates[i]) != len(candidates[j]) {
+			return len(candidates[i]) > len(candidates[j])
+		}
+		return candidates[i] < candidates[j]
+	})
+	return v.origins[candidates[0]], true
+}